@@ -6,6 +6,7 @@ const schema = z.object({
   ANDROID_WORKER_ADDR: z.string().default("127.0.0.1:50051"),
   IOS_WORKER_ADDR: z.string().default("127.0.0.1:50052"),
   GRPC_TIMEOUT_MS: z.coerce.number().int().positive().default(1200),
+  GRPC_SLOW_TIMEOUT_MS: z.coerce.number().int().positive().default(200000),
   GRPC_RETRIES: z.coerce.number().int().min(0).max(5).default(2),
   ACTION_QUEUE_IDLE_MS: z.coerce.number().int().positive().default(300000),
   MAX_UI_NODES: z.coerce.number().int().positive().default(300),
@@ -7,21 +7,77 @@ const PROTO_PATH = path.resolve(process.cwd(), "../proto/mobile.proto");
 
 type UnaryCallback<T> = (error: grpc.ServiceError | null, response: T) => void;
 
-type RawClient = grpc.Client & {
-  ListDevices(request: unknown, metadata: grpc.Metadata, options: grpc.CallOptions, callback: UnaryCallback<any>): void;
-  GetActiveApp(request: unknown, metadata: grpc.Metadata, options: grpc.CallOptions, callback: UnaryCallback<any>): void;
-  GetUITree(request: unknown, metadata: grpc.Metadata, options: grpc.CallOptions, callback: UnaryCallback<any>): void;
-  FindElements(request: unknown, metadata: grpc.Metadata, options: grpc.CallOptions, callback: UnaryCallback<any>): void;
-  Tap(request: unknown, metadata: grpc.Metadata, options: grpc.CallOptions, callback: UnaryCallback<any>): void;
-  Type(request: unknown, metadata: grpc.Metadata, options: grpc.CallOptions, callback: UnaryCallback<any>): void;
-  Swipe(request: unknown, metadata: grpc.Metadata, options: grpc.CallOptions, callback: UnaryCallback<any>): void;
-  ScreenshotStream(request: unknown, metadata?: grpc.Metadata, options?: grpc.CallOptions): grpc.ClientReadableStream<any>;
-};
+type UnaryMethod = (request: unknown, metadata: grpc.Metadata, options: grpc.CallOptions, callback: UnaryCallback<any>) => void;
+type StreamMethod = (request: unknown, metadata?: grpc.Metadata, options?: grpc.CallOptions) => grpc.ClientReadableStream<any>;
+
+type UnaryMethodName =
+  | "ListDevices"
+  | "GetActiveApp"
+  | "GetUITree"
+  | "GetSnapshotScreenshot"
+  | "FindElements"
+  | "Tap"
+  | "Type"
+  | "Swipe"
+  | "LaunchApp"
+  | "TerminateApp"
+  | "InstallApp"
+  | "UninstallApp"
+  | "ClearAppData"
+  | "OpenURL"
+  | "GetClipboard"
+  | "SetClipboard"
+  | "GetOrientation"
+  | "SetOrientation"
+  | "SetLocation"
+  | "GrantPermission"
+  | "RevokePermission"
+  | "ResetPermissions"
+  | "GetAlert"
+  | "AcceptAlert"
+  | "DismissAlert"
+  | "RegisterWatcher"
+  | "UnregisterWatcher"
+  | "ListWatchers"
+  | "GetKeyboardState"
+  | "HideKeyboard"
+  | "ExecShell"
+  | "StartRecording"
+  | "TakeScreenshot"
+  | "AnnotatedScreenshot"
+  | "GetCoordinateModel"
+  | "GetQueueStats"
+  | "GetCrashes";
+
+// Reads that change nothing on the device, so repeating one after a dropped
+// connection, a timeout or a full executor lane is harmless. Everything else,
+// including GetCrashes, which clears what it returns, is sent once.
+const IDEMPOTENT_METHODS: ReadonlySet<UnaryMethodName> = new Set<UnaryMethodName>([
+  "ListDevices",
+  "GetActiveApp",
+  "GetUITree",
+  "GetSnapshotScreenshot",
+  "FindElements",
+  "GetClipboard",
+  "GetOrientation",
+  "GetAlert",
+  "ListWatchers",
+  "GetKeyboardState",
+  "TakeScreenshot",
+  "AnnotatedScreenshot",
+  "GetCoordinateModel",
+  "GetQueueStats"
+]);
+
+type StreamMethodName = "ScreenshotStream" | "PlayRoute" | "StreamLogs" | "StopRecording";
+
+type RawClient = grpc.Client & Record<UnaryMethodName, UnaryMethod> & Record<StreamMethodName, StreamMethod>;
 
 interface ClientConfig {
   androidAddr: string;
   iosAddr: string;
   timeoutMs: number;
+  slowTimeoutMs: number;
   retries: number;
 }
 
@@ -81,46 +137,203 @@ export class MobileGrpcRouter {
   }
 
   getActiveApp(deviceId: string, request: Record<string, unknown>): Promise<any> {
-    return this.routeToDevice(deviceId, (c) => this.invoke(c, "GetActiveApp", request));
+    return this.unary(deviceId, "GetActiveApp", request);
   }
 
   getUITree(deviceId: string, request: Record<string, unknown>): Promise<any> {
-    return this.routeToDevice(deviceId, (c) => this.invoke(c, "GetUITree", request));
+    return this.unary(deviceId, "GetUITree", request);
+  }
+
+  getSnapshotScreenshot(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "GetSnapshotScreenshot", request);
   }
 
   findElements(deviceId: string, request: Record<string, unknown>): Promise<any> {
-    return this.routeToDevice(deviceId, (c) => this.invoke(c, "FindElements", request));
+    return this.unary(deviceId, "FindElements", request);
   }
 
   tap(deviceId: string, request: Record<string, unknown>): Promise<any> {
-    return this.routeToDevice(deviceId, (c) => this.invoke(c, "Tap", request));
+    return this.unary(deviceId, "Tap", request);
   }
 
   type(deviceId: string, request: Record<string, unknown>): Promise<any> {
-    return this.routeToDevice(deviceId, (c) => this.invoke(c, "Type", request));
+    return this.unary(deviceId, "Type", request);
   }
 
   swipe(deviceId: string, request: Record<string, unknown>): Promise<any> {
-    return this.routeToDevice(deviceId, (c) => this.invoke(c, "Swipe", request));
+    return this.unary(deviceId, "Swipe", request);
+  }
+
+  launchApp(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "LaunchApp", request, this.cfg.slowTimeoutMs);
+  }
+
+  terminateApp(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "TerminateApp", request, this.cfg.slowTimeoutMs);
+  }
+
+  installApp(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "InstallApp", request, this.cfg.slowTimeoutMs);
+  }
+
+  uninstallApp(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "UninstallApp", request, this.cfg.slowTimeoutMs);
+  }
+
+  clearAppData(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "ClearAppData", request, this.cfg.slowTimeoutMs);
+  }
+
+  openUrl(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "OpenURL", request, this.cfg.slowTimeoutMs);
+  }
+
+  getClipboard(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "GetClipboard", request);
+  }
+
+  setClipboard(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "SetClipboard", request);
+  }
+
+  getOrientation(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "GetOrientation", request);
+  }
+
+  setOrientation(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "SetOrientation", request);
   }
 
-  async collectScreenshotFrames(deviceId: string, request: Record<string, unknown>, maxFrames: number): Promise<any[]> {
+  setLocation(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "SetLocation", request);
+  }
+
+  grantPermission(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "GrantPermission", request, this.cfg.slowTimeoutMs);
+  }
+
+  revokePermission(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "RevokePermission", request, this.cfg.slowTimeoutMs);
+  }
+
+  resetPermissions(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "ResetPermissions", request, this.cfg.slowTimeoutMs);
+  }
+
+  getAlert(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "GetAlert", request);
+  }
+
+  acceptAlert(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "AcceptAlert", request);
+  }
+
+  dismissAlert(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "DismissAlert", request);
+  }
+
+  registerWatcher(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "RegisterWatcher", request);
+  }
+
+  unregisterWatcher(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "UnregisterWatcher", request);
+  }
+
+  listWatchers(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "ListWatchers", request);
+  }
+
+  getKeyboardState(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "GetKeyboardState", request);
+  }
+
+  hideKeyboard(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "HideKeyboard", request);
+  }
+
+  execShell(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "ExecShell", request, this.cfg.slowTimeoutMs);
+  }
+
+  startRecording(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "StartRecording", request, this.cfg.slowTimeoutMs);
+  }
+
+  takeScreenshot(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "TakeScreenshot", request);
+  }
+
+  annotatedScreenshot(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "AnnotatedScreenshot", request);
+  }
+
+  getCoordinateModel(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "GetCoordinateModel", request);
+  }
+
+  getQueueStats(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "GetQueueStats", request);
+  }
+
+  getCrashes(deviceId: string, request: Record<string, unknown>): Promise<any> {
+    return this.unary(deviceId, "GetCrashes", request);
+  }
+
+  collectScreenshotFrames(deviceId: string, request: Record<string, unknown>, maxFrames: number): Promise<any[]> {
+    return this.collect(deviceId, "ScreenshotStream", request, this.cfg.timeoutMs * 10, (events) =>
+      events.filter((e) => e.frame_meta).length >= maxFrames
+    );
+  }
+
+  collectLogs(deviceId: string, request: Record<string, unknown>, durationMs: number): Promise<any[]> {
+    return this.collect(deviceId, "StreamLogs", request, durationMs, undefined, true);
+  }
+
+  collectRoute(deviceId: string, request: Record<string, unknown>, deadlineMs: number): Promise<any[]> {
+    return this.collect(deviceId, "PlayRoute", request, deadlineMs);
+  }
+
+  collectRecording(deviceId: string, request: Record<string, unknown>, deadlineMs: number): Promise<any[]> {
+    return this.collect(deviceId, "StopRecording", request, deadlineMs);
+  }
+
+  close(): void {
+    this.android.close();
+    this.ios.close();
+  }
+
+  private async unary(deviceId: string, method: UnaryMethodName, request: Record<string, unknown>, timeoutMs?: number): Promise<any> {
     const client = await this.resolveClient(deviceId);
-    const stream = client.ScreenshotStream(request, new grpc.Metadata(), { deadline: Date.now() + this.cfg.timeoutMs * 10 });
+    return this.invoke(client, method, request, false, timeoutMs);
+  }
+
+  // collect reads a server stream until it ends or done() says enough. When
+  // deadlineIsEnd is set the deadline is how collection normally stops, as for
+  // an open-ended log tail, rather than an error.
+  private async collect(
+    deviceId: string,
+    method: StreamMethodName,
+    request: Record<string, unknown>,
+    deadlineMs: number,
+    done?: (events: any[]) => boolean,
+    deadlineIsEnd = false
+  ): Promise<any[]> {
+    const client = await this.resolveClient(deviceId);
+    const stream = client[method](request, new grpc.Metadata(), { deadline: Date.now() + deadlineMs });
 
     const events: any[] = [];
     await new Promise<void>((resolve, reject) => {
       stream.on("data", (evt) => {
         events.push(evt);
-        const metas = events.filter((e) => e.frame_meta).length;
-        if (metas >= maxFrames) {
+        if (done?.(events)) {
           stream.cancel();
           resolve();
         }
       });
       stream.on("error", (err) => {
         const grpcErr = err as grpc.ServiceError;
-        if (grpcErr.code === grpc.status.CANCELLED) {
+        if (grpcErr.code === grpc.status.CANCELLED || (grpcErr.code === grpc.status.DEADLINE_EXCEEDED && deadlineIsEnd)) {
           resolve();
           return;
         }
@@ -132,16 +345,6 @@ export class MobileGrpcRouter {
     return events;
   }
 
-  close(): void {
-    this.android.close();
-    this.ios.close();
-  }
-
-  private async routeToDevice<T>(deviceId: string, run: (client: RawClient) => Promise<T>): Promise<T> {
-    const client = await this.resolveClient(deviceId);
-    return run(client);
-  }
-
   private async resolveClient(deviceId: string): Promise<RawClient> {
     const platform = this.devicePlatform.get(deviceId);
     if (platform === "PLATFORM_ANDROID") return this.android;
@@ -158,16 +361,17 @@ export class MobileGrpcRouter {
 
   private async invoke(
     client: RawClient,
-    method: keyof RawClient,
+    method: UnaryMethodName,
     request: Record<string, unknown>,
-    suppressErrorLog = false
+    suppressErrorLog = false,
+    timeoutMs = this.cfg.timeoutMs
   ): Promise<any> {
     let lastError: unknown;
 
     for (let attempt = 0; attempt <= this.cfg.retries; attempt += 1) {
       try {
         const response = await new Promise<any>((resolve, reject) => {
-          const deadline = Date.now() + this.cfg.timeoutMs;
+          const deadline = Date.now() + timeoutMs;
           const metadata = new grpc.Metadata();
           const callback: UnaryCallback<any> = (error, resp) => {
             if (error) return reject(error);
@@ -180,11 +384,17 @@ export class MobileGrpcRouter {
       } catch (error) {
         lastError = error;
         const code = (error as grpc.ServiceError).code;
-        const retryable = code === grpc.status.UNAVAILABLE || code === grpc.status.DEADLINE_EXCEEDED;
+        const retryable =
+          IDEMPOTENT_METHODS.has(method) &&
+          (code === grpc.status.UNAVAILABLE ||
+            code === grpc.status.DEADLINE_EXCEEDED ||
+            code === grpc.status.RESOURCE_EXHAUSTED);
         if (!retryable || attempt === this.cfg.retries) {
           break;
         }
-        const backoffMs = 20 * Math.pow(2, attempt);
+        // A full executor lane rejects before running anything and says when to come back.
+        const retryAfterMs = Number((error as grpc.ServiceError).metadata?.get("retry-after-ms")[0] ?? 0);
+        const backoffMs = Math.max(20 * Math.pow(2, attempt), retryAfterMs);
         await new Promise((r) => setTimeout(r, backoffMs));
       }
     }
@@ -5,29 +5,80 @@ import { GatewayConfig } from "../config.js";
 import { MobileGrpcRouter } from "../grpc/mobileClient.js";
 import { logger } from "../logger.js";
 import { DeviceQueueManager } from "../queue/deviceQueue.js";
-import { shapeAction, shapeDevices, shapeElements, shapeScreenshotEvents, shapeTree } from "../response/shaper.js";
+import {
+  shapeAction,
+  shapeDevices,
+  shapeElements,
+  shapeImage,
+  shapeLogEvents,
+  shapeRecordingEvents,
+  shapeRouteEvents,
+  shapeScreenshotEvents,
+  shapeTree
+} from "../response/shaper.js";
 import {
   activeAppSchema,
+  alertActionSchema,
+  annotatedScreenshotSchema,
+  appSchema,
+  coordinateModelSchema,
+  crashesSchema,
+  execShellSchema,
   findElementsSchema,
+  getAlertSchema,
+  getClipboardSchema,
+  getOrientationSchema,
+  getSnapshotScreenshotSchema,
+  hideKeyboardSchema,
+  installAppSchema,
+  keyboardStateSchema,
+  launchAppSchema,
   listDevicesSchema,
+  listWatchersSchema,
+  openUrlSchema,
+  permissionSchema,
+  playRouteSchema,
+  queueStatsSchema,
+  registerWatcherSchema,
   screenshotStreamSchema,
+  setClipboardSchema,
+  setLocationSchema,
+  setOrientationSchema,
+  startRecordingSchema,
+  stopRecordingSchema,
+  streamLogsSchema,
   swipeSchema,
+  takeScreenshotSchema,
   tapSchema,
   typeSchema,
-  uiTreeSchema
+  uiTreeSchema,
+  unregisterWatcherSchema
 } from "../validation.js";
 
+const DEFAULT_LOG_DURATION_MS = 5000;
+const DEFAULT_LOG_ENTRIES = 200;
+
 function asMcpText(result: unknown) {
   return {
     content: [{ type: "text", text: JSON.stringify(result) }]
   };
 }
 
+function asMcpImage(image: { data: string; mimeType: string; meta: unknown }) {
+  return {
+    content: [
+      { type: "image" as const, data: image.data, mimeType: image.mimeType },
+      { type: "text" as const, text: JSON.stringify(image.meta) }
+    ]
+  };
+}
+
 export async function startGateway(config: GatewayConfig): Promise<void> {
   const grpc = new MobileGrpcRouter({
     androidAddr: config.ANDROID_WORKER_ADDR,
     iosAddr: config.IOS_WORKER_ADDR,
     timeoutMs: config.GRPC_TIMEOUT_MS,
+    slowTimeoutMs: config.GRPC_SLOW_TIMEOUT_MS,
     retries: config.GRPC_RETRIES
   });
 
@@ -60,7 +111,40 @@ export async function startGateway(config: GatewayConfig): Promise<void> {
       { name: "tap", description: "Tap by refId, selector, or coordinates", inputSchema: defaultInputSchema },
       { name: "type", description: "Type text after targeting element", inputSchema: defaultInputSchema },
       { name: "swipe", description: "Swipe on screen", inputSchema: defaultInputSchema },
-      { name: "screenshot_stream", description: "Collect screenshot stream metadata", inputSchema: defaultInputSchema }
+      { name: "screenshot_stream", description: "Collect screenshot stream metadata", inputSchema: defaultInputSchema },
+      { name: "get_snapshot_screenshot", description: "Get the screenshot captured with a UI tree snapshot", inputSchema: defaultInputSchema },
+      { name: "take_screenshot", description: "Capture a screenshot, optionally cropped to bounds or an element", inputSchema: defaultInputSchema },
+      { name: "annotated_screenshot", description: "Screenshot with numbered marks on clickable elements", inputSchema: defaultInputSchema },
+      { name: "launch_app", description: "Launch an app by package or bundle id", inputSchema: defaultInputSchema },
+      { name: "terminate_app", description: "Stop a running app", inputSchema: defaultInputSchema },
+      { name: "install_app", description: "Install an app from a path on the worker host", inputSchema: defaultInputSchema },
+      { name: "uninstall_app", description: "Uninstall an app", inputSchema: defaultInputSchema },
+      { name: "clear_app_data", description: "Clear an app's data", inputSchema: defaultInputSchema },
+      { name: "open_url", description: "Open a URL or deep link", inputSchema: defaultInputSchema },
+      { name: "get_clipboard", description: "Read the device clipboard", inputSchema: defaultInputSchema },
+      { name: "set_clipboard", description: "Write the device clipboard", inputSchema: defaultInputSchema },
+      { name: "get_orientation", description: "Get screen orientation", inputSchema: defaultInputSchema },
+      { name: "set_orientation", description: "Rotate the screen", inputSchema: defaultInputSchema },
+      { name: "set_location", description: "Set a mock GPS location", inputSchema: defaultInputSchema },
+      { name: "play_route", description: "Replay a sequence of GPS waypoints", inputSchema: defaultInputSchema },
+      { name: "grant_permission", description: "Grant an app permission", inputSchema: defaultInputSchema },
+      { name: "revoke_permission", description: "Revoke an app permission", inputSchema: defaultInputSchema },
+      { name: "reset_permissions", description: "Reset app permissions so the app prompts again", inputSchema: defaultInputSchema },
+      { name: "get_alert", description: "Read the system alert, if any", inputSchema: defaultInputSchema },
+      { name: "accept_alert", description: "Accept the system alert", inputSchema: defaultInputSchema },
+      { name: "dismiss_alert", description: "Dismiss the system alert", inputSchema: defaultInputSchema },
+      { name: "register_watcher", description: "Auto-tap interruptions matching a selector", inputSchema: defaultInputSchema },
+      { name: "unregister_watcher", description: "Remove a watcher", inputSchema: defaultInputSchema },
+      { name: "list_watchers", description: "List watchers for a device", inputSchema: defaultInputSchema },
+      { name: "get_keyboard_state", description: "Check whether the soft keyboard is shown", inputSchema: defaultInputSchema },
+      { name: "hide_keyboard", description: "Hide the soft keyboard", inputSchema: defaultInputSchema },
+      { name: "exec_shell", description: "Run an allowlisted shell command (Android)", inputSchema: defaultInputSchema },
+      { name: "stream_logs", description: "Collect device log entries for a short window", inputSchema: defaultInputSchema },
+      { name: "start_recording", description: "Start a screen recording", inputSchema: defaultInputSchema },
+      { name: "stop_recording", description: "Stop a screen recording and report its files", inputSchema: defaultInputSchema },
+      { name: "get_coordinate_model", description: "Get screen size, scale and tap coordinate space", inputSchema: defaultInputSchema },
+      { name: "get_queue_stats", description: "Get per-lane device executor queue depth", inputSchema: defaultInputSchema },
+      { name: "get_crashes", description: "Get app crashes and ANRs not attributed to an action", inputSchema: defaultInputSchema }
     ]
   }));
 
@@ -122,6 +206,204 @@ export async function startGateway(config: GatewayConfig): Promise<void> {
           return asMcpText(shapeScreenshotEvents(events));
         }
 
+        case "get_snapshot_screenshot": {
+          const parsed = getSnapshotScreenshotSchema.parse(args);
+          const resp = await grpc.getSnapshotScreenshot(parsed.device_id, parsed);
+          return asMcpImage(shapeImage(resp));
+        }
+
+        case "take_screenshot": {
+          const parsed = takeScreenshotSchema.parse(args);
+          const resp = await grpc.takeScreenshot(parsed.device_id, parsed);
+          return asMcpImage(shapeImage(resp));
+        }
+
+        case "annotated_screenshot": {
+          const parsed = annotatedScreenshotSchema.parse(args);
+          const resp = await grpc.annotatedScreenshot(parsed.device_id, parsed);
+          return asMcpImage(shapeImage(resp));
+        }
+
+        case "launch_app": {
+          const parsed = launchAppSchema.parse(args);
+          const resp = await queue.enqueue(parsed.device_id, config.GRPC_SLOW_TIMEOUT_MS, () => grpc.launchApp(parsed.device_id, parsed));
+          return asMcpText(shapeAction(resp));
+        }
+
+        case "terminate_app": {
+          const parsed = appSchema.parse(args);
+          const resp = await queue.enqueue(parsed.device_id, config.GRPC_SLOW_TIMEOUT_MS, () => grpc.terminateApp(parsed.device_id, parsed));
+          return asMcpText(shapeAction(resp));
+        }
+
+        case "install_app": {
+          const parsed = installAppSchema.parse(args);
+          const resp = await queue.enqueue(parsed.device_id, config.GRPC_SLOW_TIMEOUT_MS, () => grpc.installApp(parsed.device_id, parsed));
+          return asMcpText(shapeAction(resp));
+        }
+
+        case "uninstall_app": {
+          const parsed = appSchema.parse(args);
+          const resp = await queue.enqueue(parsed.device_id, config.GRPC_SLOW_TIMEOUT_MS, () => grpc.uninstallApp(parsed.device_id, parsed));
+          return asMcpText(shapeAction(resp));
+        }
+
+        case "clear_app_data": {
+          const parsed = appSchema.parse(args);
+          const resp = await queue.enqueue(parsed.device_id, config.GRPC_SLOW_TIMEOUT_MS, () => grpc.clearAppData(parsed.device_id, parsed));
+          return asMcpText(shapeAction(resp));
+        }
+
+        case "open_url": {
+          const parsed = openUrlSchema.parse(args);
+          const resp = await queue.enqueue(parsed.device_id, config.GRPC_SLOW_TIMEOUT_MS, () => grpc.openUrl(parsed.device_id, parsed));
+          return asMcpText(shapeAction(resp));
+        }
+
+        case "get_clipboard": {
+          const parsed = getClipboardSchema.parse(args);
+          return asMcpText(await grpc.getClipboard(parsed.device_id, parsed));
+        }
+
+        case "set_clipboard": {
+          const parsed = setClipboardSchema.parse(args);
+          const timeoutMs = parsed.options?.timeout_ms ?? config.GRPC_TIMEOUT_MS;
+          const resp = await queue.enqueue(parsed.device_id, timeoutMs, () => grpc.setClipboard(parsed.device_id, parsed));
+          return asMcpText(shapeAction(resp));
+        }
+
+        case "get_orientation": {
+          const parsed = getOrientationSchema.parse(args);
+          return asMcpText(await grpc.getOrientation(parsed.device_id, parsed));
+        }
+
+        case "set_orientation": {
+          const parsed = setOrientationSchema.parse(args);
+          const timeoutMs = parsed.options?.timeout_ms ?? config.GRPC_TIMEOUT_MS;
+          const resp = await queue.enqueue(parsed.device_id, timeoutMs, () => grpc.setOrientation(parsed.device_id, parsed));
+          return asMcpText(shapeAction(resp));
+        }
+
+        case "set_location": {
+          const parsed = setLocationSchema.parse(args);
+          const timeoutMs = parsed.options?.timeout_ms ?? config.GRPC_TIMEOUT_MS;
+          const resp = await queue.enqueue(parsed.device_id, timeoutMs, () => grpc.setLocation(parsed.device_id, parsed));
+          return asMcpText(shapeAction(resp));
+        }
+
+        case "play_route": {
+          // Routes run for as long as their offsets say, so they stay out of the
+          // action queue and get a deadline sized to the route.
+          const parsed = playRouteSchema.parse(args);
+          const lastOffset = Math.max(...parsed.waypoints.map((wp) => wp.offset_ms));
+          const deadlineMs = lastOffset / (parsed.speed_multiplier ?? 1) + config.GRPC_SLOW_TIMEOUT_MS;
+          const events = await grpc.collectRoute(parsed.device_id, parsed, deadlineMs);
+          return asMcpText(shapeRouteEvents(events));
+        }
+
+        case "grant_permission": {
+          const parsed = permissionSchema.parse(args);
+          const resp = await queue.enqueue(parsed.device_id, config.GRPC_SLOW_TIMEOUT_MS, () => grpc.grantPermission(parsed.device_id, parsed));
+          return asMcpText(shapeAction(resp));
+        }
+
+        case "revoke_permission": {
+          const parsed = permissionSchema.parse(args);
+          const resp = await queue.enqueue(parsed.device_id, config.GRPC_SLOW_TIMEOUT_MS, () => grpc.revokePermission(parsed.device_id, parsed));
+          return asMcpText(shapeAction(resp));
+        }
+
+        case "reset_permissions": {
+          const parsed = permissionSchema.parse(args);
+          const resp = await queue.enqueue(parsed.device_id, config.GRPC_SLOW_TIMEOUT_MS, () => grpc.resetPermissions(parsed.device_id, parsed));
+          return asMcpText(shapeAction(resp));
+        }
+
+        case "get_alert": {
+          const parsed = getAlertSchema.parse(args);
+          return asMcpText(await grpc.getAlert(parsed.device_id, parsed));
+        }
+
+        case "accept_alert": {
+          const parsed = alertActionSchema.parse(args);
+          const timeoutMs = parsed.options?.timeout_ms ?? config.GRPC_TIMEOUT_MS;
+          const resp = await queue.enqueue(parsed.device_id, timeoutMs, () => grpc.acceptAlert(parsed.device_id, parsed));
+          return asMcpText(shapeAction(resp));
+        }
+
+        case "dismiss_alert": {
+          const parsed = alertActionSchema.parse(args);
+          const timeoutMs = parsed.options?.timeout_ms ?? config.GRPC_TIMEOUT_MS;
+          const resp = await queue.enqueue(parsed.device_id, timeoutMs, () => grpc.dismissAlert(parsed.device_id, parsed));
+          return asMcpText(shapeAction(resp));
+        }
+
+        case "register_watcher": {
+          const parsed = registerWatcherSchema.parse(args);
+          return asMcpText(await grpc.registerWatcher(parsed.device_id, parsed));
+        }
+
+        case "unregister_watcher": {
+          const parsed = unregisterWatcherSchema.parse(args);
+          return asMcpText(await grpc.unregisterWatcher(parsed.device_id, parsed));
+        }
+
+        case "list_watchers": {
+          const parsed = listWatchersSchema.parse(args);
+          return asMcpText(await grpc.listWatchers(parsed.device_id, parsed));
+        }
+
+        case "get_keyboard_state": {
+          const parsed = keyboardStateSchema.parse(args);
+          return asMcpText(await grpc.getKeyboardState(parsed.device_id, parsed));
+        }
+
+        case "hide_keyboard": {
+          const parsed = hideKeyboardSchema.parse(args);
+          const timeoutMs = parsed.options?.timeout_ms ?? config.GRPC_TIMEOUT_MS;
+          const resp = await queue.enqueue(parsed.device_id, timeoutMs, () => grpc.hideKeyboard(parsed.device_id, parsed));
+          return asMcpText(shapeAction(resp));
+        }
+
+        case "exec_shell": {
+          const parsed = execShellSchema.parse(args);
+          const resp = await queue.enqueue(parsed.device_id, config.GRPC_SLOW_TIMEOUT_MS, () => grpc.execShell(parsed.device_id, parsed));
+          return asMcpText(resp);
+        }
+
+        case "stream_logs": {
+          const { duration_ms, ...parsed } = streamLogsSchema.parse(args);
+          const request = { ...parsed, max_entries: parsed.max_entries ?? DEFAULT_LOG_ENTRIES };
+          const events = await grpc.collectLogs(parsed.device_id, request, duration_ms ?? DEFAULT_LOG_DURATION_MS);
+          return asMcpText(shapeLogEvents(events));
+        }
+
+        case "start_recording": {
+          const parsed = startRecordingSchema.parse(args);
+          return asMcpText(await grpc.startRecording(parsed.device_id, parsed));
+        }
+
+        case "stop_recording": {
+          const parsed = stopRecordingSchema.parse(args);
+          const events = await grpc.collectRecording(parsed.device_id, parsed, config.GRPC_SLOW_TIMEOUT_MS);
+          return asMcpText(shapeRecordingEvents(events));
+        }
+
+        case "get_coordinate_model": {
+          const parsed = coordinateModelSchema.parse(args);
+          return asMcpText(await grpc.getCoordinateModel(parsed.device_id, parsed));
+        }
+
+        case "get_queue_stats": {
+          const parsed = queueStatsSchema.parse(args);
+          return asMcpText(await grpc.getQueueStats(parsed.device_id, parsed));
+        }
+
+        case "get_crashes": {
+          const parsed = crashesSchema.parse(args);
+          return asMcpText(await grpc.getCrashes(parsed.device_id, parsed));
+        }
+
         default:
           throw new Error(`unknown tool: ${name}`);
       }
@@ -26,7 +26,8 @@ export function shapeTree(resp: any, nodeLimit: number): any {
     bounds: n.bounds,
     enabled: n.enabled,
     clickable: n.clickable,
-    visible: n.visible
+    visible: n.visible,
+    focused: n.focused
   }));
 
   return {
@@ -35,6 +36,8 @@ export function shapeTree(resp: any, nodeLimit: number): any {
     expires_at_unix_ms: resp.expires_at_unix_ms,
     total_nodes: resp.total_nodes,
     next_cursor: resp.next_cursor,
+    orientation: resp.orientation,
+    screenshot: resp.screenshot,
     nodes
   };
 }
@@ -83,6 +86,7 @@ export function shapeAction(resp: any): any {
 export function shapeScreenshotEvents(events: any[]): any {
   const frames: any[] = [];
   let totalChunks = 0;
+  let heartbeat: any = undefined;
   for (const event of events) {
     if (event.frame_meta) {
       frames.push(event.frame_meta);
@@ -90,10 +94,66 @@ export function shapeScreenshotEvents(events: any[]): any {
     if (event.chunk) {
       totalChunks += 1;
     }
+    if (event.heartbeat) {
+      heartbeat = event.heartbeat;
+    }
   }
   return {
     frame_count: frames.length,
     chunk_count: totalChunks,
+    frames_skipped: heartbeat?.frames_skipped ?? 0,
+    frames_dropped: heartbeat?.frames_dropped ?? 0,
+    effective_fps: heartbeat?.effective_fps,
     frames
   };
 }
+
+export function shapeLogEvents(events: any[]): any {
+  const entries = events.filter((e) => e.entry).map((e) => e.entry);
+  const end = events.find((e) => e.end)?.end;
+  return {
+    entry_count: entries.length,
+    end_reason: end?.reason,
+    entries
+  };
+}
+
+export function shapeRouteEvents(events: any[]): any {
+  const progress = events.filter((e) => e.progress).map((e) => e.progress);
+  const end = events.find((e) => e.end)?.end;
+  return {
+    waypoints_applied: progress.length,
+    end_reason: end?.reason,
+    last: progress[progress.length - 1]
+  };
+}
+
+// Recording bytes stay on the worker host; the tool reports where each segment
+// was written instead of relaying the video through MCP.
+export function shapeRecordingEvents(events: any[]): any {
+  const segments = events
+    .filter((e) => e.segment_meta)
+    .map((e) => ({
+      segment_index: e.segment_meta.segment_index,
+      mime_type: e.segment_meta.mime_type,
+      total_bytes: e.segment_meta.total_bytes,
+      host_path: e.segment_meta.host_path
+    }));
+  const end = events.find((e) => e.end)?.end;
+  return {
+    segment_count: segments.length,
+    end_reason: end?.reason,
+    segments
+  };
+}
+
+// shapeImage splits an image response into the image bytes and the remaining
+// fields, so the tool can return the picture as MCP image content.
+export function shapeImage(resp: any): { data: string; mimeType: string; meta: any } {
+  const { data, ...meta } = resp;
+  return {
+    data: Buffer.from(data ?? []).toString("base64"),
+    mimeType: resp.mime_type,
+    meta
+  };
+}
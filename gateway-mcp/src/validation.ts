@@ -51,6 +51,7 @@ export const uiTreeSchema = z.object({
   node_limit: z.number().int().positive().max(1000).optional(),
   depth_limit: z.number().int().positive().max(100).optional(),
   cursor: z.string().optional(),
+  include_screenshot: z.boolean().optional(),
   options: requestOptions
 });
 
@@ -68,13 +69,31 @@ export const findElementsSchema = z.object({
   }
 });
 
+const coordinateSpace = z.enum([
+  "COORDINATE_SPACE_UNSPECIFIED",
+  "COORDINATE_SPACE_PIXELS",
+  "COORDINATE_SPACE_POINTS",
+  "COORDINATE_SPACE_SCREENSHOT",
+  "COORDINATE_SPACE_NORMALIZED"
+]);
+
+const spacedCoordinates = z.object({
+  x: z.number().int().optional(),
+  y: z.number().int().optional(),
+  normalized_x: z.number().min(0).max(1).optional(),
+  normalized_y: z.number().min(0).max(1).optional()
+});
+
 export const tapSchema = z.object({
   device_id: z.string().min(1),
   ref_id: z.string().optional(),
-  coordinates: z.object({ x: z.number().int(), y: z.number().int() }).optional(),
+  coordinates: spacedCoordinates.optional(),
+  coordinate_space: coordinateSpace.optional(),
+  screenshot_scale: z.number().positive().finite().optional(),
   selector: selectorSchema.optional(),
   snapshot_id: z.string().optional(),
   tap_count: z.number().int().positive().max(5).optional(),
+  hide_keyboard_if_covered: z.boolean().optional(),
   options: requestOptions
 });
 
@@ -86,13 +105,16 @@ export const typeSchema = z.object({
   snapshot_id: z.string().optional(),
   text: z.string(),
   clear_before_type: z.boolean().optional(),
+  replace_text: z.boolean().optional(),
   options: requestOptions
 });
 
 export const swipeSchema = z.object({
   device_id: z.string().min(1),
-  start: z.object({ x: z.number().int(), y: z.number().int() }).optional(),
-  end: z.object({ x: z.number().int(), y: z.number().int() }).optional(),
+  start: spacedCoordinates.optional(),
+  end: spacedCoordinates.optional(),
+  coordinate_space: coordinateSpace.optional(),
+  screenshot_scale: z.number().positive().finite().optional(),
   direction: z.enum(["DIRECTION_UNSPECIFIED", "DIRECTION_UP", "DIRECTION_DOWN", "DIRECTION_LEFT", "DIRECTION_RIGHT"]).optional(),
   distance_px: z.number().int().positive().optional(),
   duration_ms: z.number().int().positive().max(5000).optional(),
@@ -106,5 +128,210 @@ export const screenshotStreamSchema = z.object({
   max_width: z.number().int().positive().optional(),
   max_height: z.number().int().positive().optional(),
   max_frames: z.number().int().positive().max(30).optional(),
+  skip_unchanged: z.boolean().optional(),
+  change_threshold: z.number().int().min(0).max(64).optional(),
+  options: requestOptions
+});
+
+const deviceOnly = z.object({
+  device_id: z.string().min(1),
+  options: requestOptions
+});
+
+export const getSnapshotScreenshotSchema = z.object({
+  device_id: z.string().min(1),
+  snapshot_id: z.string().min(1)
+});
+
+export const launchAppSchema = z.object({
+  device_id: z.string().min(1),
+  app_id: z.string().min(1),
+  activity: z.string().optional(),
+  stop_existing: z.boolean().optional(),
+  options: requestOptions
+});
+
+export const appSchema = z.object({
+  device_id: z.string().min(1),
+  app_id: z.string().min(1),
+  options: requestOptions
+});
+
+export const installAppSchema = z.object({
+  device_id: z.string().min(1),
+  app_path: z.string().min(1),
+  options: requestOptions
+});
+
+export const openUrlSchema = z.object({
+  device_id: z.string().min(1),
+  url: z.string().min(1),
+  app_id: z.string().optional(),
+  wait_for_app: z.boolean().optional(),
+  options: requestOptions
+});
+
+export const getClipboardSchema = deviceOnly;
+
+export const setClipboardSchema = z.object({
+  device_id: z.string().min(1),
+  text: z.string(),
+  options: requestOptions
+});
+
+export const getOrientationSchema = deviceOnly;
+
+export const setOrientationSchema = z.object({
+  device_id: z.string().min(1),
+  orientation: z.enum([
+    "ORIENTATION_PORTRAIT",
+    "ORIENTATION_LANDSCAPE",
+    "ORIENTATION_PORTRAIT_REVERSE",
+    "ORIENTATION_LANDSCAPE_REVERSE"
+  ]),
+  options: requestOptions
+});
+
+const location = z.object({
+  latitude: z.number().min(-90).max(90),
+  longitude: z.number().min(-180).max(180),
+  altitude: z.number().optional()
+});
+
+export const setLocationSchema = z.object({
+  device_id: z.string().min(1),
+  location,
+  options: requestOptions
+});
+
+export const playRouteSchema = z.object({
+  device_id: z.string().min(1),
+  waypoints: z.array(z.object({ location, offset_ms: z.number().int().min(0) })).min(1).max(1000),
+  speed_multiplier: z.number().positive().finite().optional(),
+  options: requestOptions
+});
+
+export const permissionSchema = z.object({
+  device_id: z.string().min(1),
+  app_id: z.string().min(1),
+  permission: z.enum([
+    "PERMISSION_UNSPECIFIED",
+    "PERMISSION_CAMERA",
+    "PERMISSION_MICROPHONE",
+    "PERMISSION_LOCATION",
+    "PERMISSION_LOCATION_ALWAYS",
+    "PERMISSION_CONTACTS",
+    "PERMISSION_CALENDAR",
+    "PERMISSION_PHOTOS",
+    "PERMISSION_NOTIFICATIONS"
+  ]).optional(),
   options: requestOptions
 });
+
+export const getAlertSchema = deviceOnly;
+
+export const alertActionSchema = z.object({
+  device_id: z.string().min(1),
+  button_label: z.string().optional(),
+  options: requestOptions
+});
+
+export const registerWatcherSchema = z.object({
+  device_id: z.string().min(1),
+  watcher: z.object({
+    name: z.string().optional(),
+    when: selectorSchema,
+    tap: selectorSchema.optional(),
+    max_triggers: z.number().int().min(0).optional()
+  }),
+  options: requestOptions
+});
+
+export const unregisterWatcherSchema = z.object({
+  device_id: z.string().min(1),
+  watcher_id: z.string().min(1),
+  options: requestOptions
+});
+
+export const listWatchersSchema = deviceOnly;
+
+export const keyboardStateSchema = deviceOnly;
+
+export const hideKeyboardSchema = deviceOnly;
+
+export const execShellSchema = z.object({
+  device_id: z.string().min(1),
+  argv: z.array(z.string()).min(1),
+  options: requestOptions
+});
+
+export const streamLogsSchema = z.object({
+  device_id: z.string().min(1),
+  tags: z.array(z.string()).optional(),
+  min_level: z.enum([
+    "LOG_LEVEL_UNSPECIFIED",
+    "LOG_LEVEL_VERBOSE",
+    "LOG_LEVEL_DEBUG",
+    "LOG_LEVEL_INFO",
+    "LOG_LEVEL_WARN",
+    "LOG_LEVEL_ERROR",
+    "LOG_LEVEL_FATAL"
+  ]).optional(),
+  package_name: z.string().optional(),
+  predicate: z.string().optional(),
+  since_unix_ms: z.number().int().min(0).optional(),
+  max_entries: z.number().int().positive().max(1000).optional(),
+  duration_ms: z.number().int().positive().max(60000).optional(),
+  options: requestOptions
+});
+
+export const startRecordingSchema = z.object({
+  device_id: z.string().min(1),
+  bit_rate_mbps: z.number().int().positive().max(100).optional(),
+  max_duration_sec: z.number().int().positive().optional()
+});
+
+export const stopRecordingSchema = z.object({
+  device_id: z.string().min(1),
+  recording_id: z.string().min(1)
+});
+
+const imageFormat = z.enum(["IMAGE_FORMAT_UNSPECIFIED", "IMAGE_FORMAT_PNG", "IMAGE_FORMAT_JPEG"]);
+
+export const takeScreenshotSchema = z.object({
+  device_id: z.string().min(1),
+  format: imageFormat.optional(),
+  jpeg_quality: z.number().int().min(1).max(100).optional(),
+  crop: z.object({
+    left: z.number().int(),
+    top: z.number().int(),
+    right: z.number().int(),
+    bottom: z.number().int()
+  }).optional(),
+  ref_id: z.string().optional(),
+  snapshot_id: z.string().optional(),
+  options: requestOptions
+}).superRefine((value, ctx) => {
+  if (value.crop && value.ref_id) {
+    ctx.addIssue({ code: z.ZodIssueCode.custom, message: "crop and ref_id are mutually exclusive" });
+  }
+});
+
+export const annotatedScreenshotSchema = z.object({
+  device_id: z.string().min(1),
+  format: imageFormat.optional(),
+  jpeg_quality: z.number().int().min(1).max(100).optional(),
+  max_width: z.number().int().positive().optional(),
+  max_height: z.number().int().positive().optional(),
+  options: requestOptions
+});
+
+export const coordinateModelSchema = deviceOnly;
+
+export const queueStatsSchema = z.object({
+  device_id: z.string().min(1)
+});
+
+export const crashesSchema = z.object({
+  device_id: z.string().min(1)
+});
@@ -0,0 +1,274 @@
+// Package executor runs a device's automation jobs one at a time, in priority
+// lanes, with bounded queues.
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type JobFunc func(context.Context) (any, error)
+
+// Lane is a job's priority class. Lower lanes run first.
+type Lane int
+
+const (
+	// LaneInteractive is for actions that change the device: taps, typing,
+	// app lifecycle.
+	LaneInteractive Lane = iota
+	// LaneQuery is for one-off reads such as hierarchy dumps and screenshots.
+	LaneQuery
+	// LaneBackground is for streams that submit work continuously.
+	LaneBackground
+
+	laneCount
+)
+
+var laneNames = [laneCount]string{"interactive", "query", "background"}
+
+func (l Lane) String() string {
+	if l < 0 || l >= laneCount {
+		return "unknown"
+	}
+	return laneNames[l]
+}
+
+// Lanes lists every lane in priority order.
+func Lanes() []Lane {
+	return []Lane{LaneInteractive, LaneQuery, LaneBackground}
+}
+
+// interactiveBurst is how many interactive jobs may run back to back while
+// query or background work waits, and queryBurst is how many query jobs may
+// run back to back while background work waits, so a busy agent can slow other
+// lanes but never freeze them.
+const (
+	interactiveBurst = 8
+	queryBurst       = 4
+)
+
+// Retry hints are derived from the average job duration and clamped so a
+// rejected client neither spins nor stalls.
+const (
+	minRetryAfter = 50 * time.Millisecond
+	maxRetryAfter = 5 * time.Second
+)
+
+var errExecutorClosed = errors.New("executor closed")
+
+// QueueFullError is returned by Submit when the job's lane is at its maximum
+// depth. The job was not queued.
+type QueueFullError struct {
+	Lane       Lane
+	Depth      int
+	RetryAfter time.Duration
+}
+
+func (e *QueueFullError) Error() string {
+	return fmt.Sprintf("%s queue full (%d jobs waiting), retry after %s", e.Lane, e.Depth, e.RetryAfter)
+}
+
+type job struct {
+	ctx    context.Context
+	fn     JobFunc
+	res    chan result
+	queued time.Time
+}
+
+type result struct {
+	value any
+	err   error
+}
+
+// Executor runs one job at a time per device. Interactive jobs run first, so a
+// tap usually waits behind only the job already in flight; after
+// interactiveBurst of them in a row, one waiting query or background job gets a
+// turn. Queries and background jobs share those turns, with background getting
+// every fifth while both are queued.
+type Executor struct {
+	maxDepth int
+
+	mu                sync.Mutex
+	cond              *sync.Cond
+	queues            [laneCount][]*job
+	interactiveStreak int
+	queryStreak       int
+	avgRun            time.Duration
+	closed            bool
+	wg                sync.WaitGroup
+}
+
+// NewExecutor returns an executor that holds at most maxDepth waiting jobs per
+// lane; Submit rejects further jobs with a QueueFullError instead of blocking.
+// A maxDepth of zero or less means no limit.
+func NewExecutor(maxDepth int) *Executor {
+	e := &Executor{maxDepth: maxDepth}
+	e.cond = sync.NewCond(&e.mu)
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		for {
+			j, ok := e.next()
+			if !ok {
+				return
+			}
+			wait := time.Since(j.queued)
+			if j.ctx.Err() != nil {
+				timingFrom(j.ctx).add(wait, 0)
+				j.res <- result{err: j.ctx.Err()}
+				close(j.res)
+				continue
+			}
+			timingFrom(j.ctx).begin()
+			started := time.Now()
+			v, err := j.fn(j.ctx)
+			run := time.Since(started)
+			e.observe(run)
+			timingFrom(j.ctx).add(wait, run)
+			j.res <- result{value: v, err: err}
+			close(j.res)
+		}
+	}()
+	return e
+}
+
+func (e *Executor) Submit(ctx context.Context, lane Lane, fn JobFunc) (any, error) {
+	if lane < 0 || lane >= laneCount {
+		lane = LaneQuery
+	}
+	j := &job{ctx: ctx, fn: fn, res: make(chan result, 1), queued: time.Now()}
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return nil, errExecutorClosed
+	}
+	if e.maxDepth > 0 && len(e.queues[lane]) >= e.maxDepth {
+		err := &QueueFullError{Lane: lane, Depth: len(e.queues[lane]), RetryAfter: e.retryAfter(lane)}
+		e.mu.Unlock()
+		timingFrom(ctx).reject(err)
+		return nil, err
+	}
+	e.queues[lane] = append(e.queues[lane], j)
+	e.cond.Signal()
+	e.mu.Unlock()
+
+	select {
+	case out := <-j.res:
+		return out.value, out.err
+	case <-ctx.Done():
+		// A job that has not started yet is withdrawn so it stops counting
+		// towards queue depth.
+		e.withdraw(lane, j)
+		return nil, errors.Join(ctx.Err(), errors.New("executor wait cancelled"))
+	}
+}
+
+// Depth returns the number of jobs waiting in lane, excluding the one running.
+func (e *Executor) Depth(lane Lane) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if lane < 0 || lane >= laneCount {
+		return 0
+	}
+	return len(e.queues[lane])
+}
+
+// Close stops accepting jobs, runs the ones already queued and waits for them.
+func (e *Executor) Close() {
+	e.mu.Lock()
+	e.closed = true
+	e.cond.Broadcast()
+	e.mu.Unlock()
+	e.wg.Wait()
+}
+
+// next blocks until a job is available and dequeues it by priority. It reports
+// false once the executor is closed and drained.
+func (e *Executor) next() (*job, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for {
+		if j := e.pick(); j != nil {
+			return j, true
+		}
+		if e.closed {
+			return nil, false
+		}
+		e.cond.Wait()
+	}
+}
+
+func (e *Executor) pick() *job {
+	lowerWaiting := len(e.queues[LaneQuery]) > 0 || len(e.queues[LaneBackground]) > 0
+	if len(e.queues[LaneInteractive]) > 0 && (!lowerWaiting || e.interactiveStreak < interactiveBurst) {
+		if lowerWaiting {
+			e.interactiveStreak++
+		}
+		return e.pop(LaneInteractive)
+	}
+	e.interactiveStreak = 0
+	backgroundWaiting := len(e.queues[LaneBackground]) > 0
+	if len(e.queues[LaneQuery]) > 0 && (!backgroundWaiting || e.queryStreak < queryBurst) {
+		if backgroundWaiting {
+			e.queryStreak++
+		}
+		return e.pop(LaneQuery)
+	}
+	if backgroundWaiting {
+		e.queryStreak = 0
+		return e.pop(LaneBackground)
+	}
+	return nil
+}
+
+func (e *Executor) pop(lane Lane) *job {
+	j := e.queues[lane][0]
+	e.queues[lane][0] = nil
+	e.queues[lane] = e.queues[lane][1:]
+	return j
+}
+
+func (e *Executor) withdraw(lane Lane, target *job) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for i, j := range e.queues[lane] {
+		if j == target {
+			e.queues[lane] = append(e.queues[lane][:i], e.queues[lane][i+1:]...)
+			timingFrom(j.ctx).add(time.Since(j.queued), 0)
+			return
+		}
+	}
+}
+
+// observe folds a finished job's duration into the running average used for
+// retry hints.
+func (e *Executor) observe(run time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.avgRun == 0 {
+		e.avgRun = run
+		return
+	}
+	e.avgRun = (e.avgRun*7 + run*3) / 10
+}
+
+// retryAfter estimates when a job in lane would get a slot: every job queued in
+// it and in the lanes ahead of it, plus the one in flight, at the average job
+// duration. The caller must hold e.mu.
+func (e *Executor) retryAfter(lane Lane) time.Duration {
+	ahead := 1
+	for l := LaneInteractive; l <= lane; l++ {
+		ahead += len(e.queues[l])
+	}
+	hint := time.Duration(ahead) * e.avgRun
+	if hint < minRetryAfter {
+		return minRetryAfter
+	}
+	if hint > maxRetryAfter {
+		return maxRetryAfter
+	}
+	return hint
+}
@@ -0,0 +1,203 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds or the test times out.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// block occupies the executor until the returned release is called, so jobs
+// can be queued in a known order.
+func block(t *testing.T, e *Executor) (release func()) {
+	t.Helper()
+	started, gate := make(chan struct{}), make(chan struct{})
+	go e.Submit(context.Background(), LaneInteractive, func(context.Context) (any, error) {
+		close(started)
+		<-gate
+		return nil, nil
+	})
+	<-started
+	var once sync.Once
+	return func() { once.Do(func() { close(gate) }) }
+}
+
+// enqueue submits a job on lane that records name when it runs, and waits
+// until it is queued.
+func enqueue(t *testing.T, e *Executor, wg *sync.WaitGroup, lane Lane, name string, record func(string)) {
+	t.Helper()
+	depth := e.Depth(lane)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = e.Submit(context.Background(), lane, func(context.Context) (any, error) {
+			record(name)
+			return nil, nil
+		})
+	}()
+	waitFor(t, "job "+name+" to queue", func() bool { return e.Depth(lane) == depth+1 })
+}
+
+func TestExecutorOrder(t *testing.T) {
+	lanes := map[byte]Lane{'I': LaneInteractive, 'Q': LaneQuery, 'B': LaneBackground}
+	tests := []struct {
+		name   string
+		queued string
+		want   string
+	}{
+		{"lanes run in priority order", "BQI", "IQB"},
+		{"jobs in a lane run in submit order", "QQQ", "QQQ"},
+		{"interactive overtakes queued work", "QBII", "IIQB"},
+		{"query burst yields to background", "QQQQQQB", "QQQQBQQ"},
+		{"query runs freely without background", "QQQQQQ", "QQQQQQ"},
+		{"interactive burst yields to a query", "IIIIIIIIIIQ", "IIIIIIIIQII"},
+		{"interactive burst yields to background", "IIIIIIIIIIB", "IIIIIIIIBII"},
+		{"interactive runs freely without other work", "IIIIIIIIII", "IIIIIIIIII"},
+		{"background is served under interactive and query load", strings.Repeat("I", 40) + strings.Repeat("Q", 8) + "B",
+			strings.Repeat("I", 8) + "Q" + strings.Repeat("I", 8) + "Q" + strings.Repeat("I", 8) + "Q" +
+				strings.Repeat("I", 8) + "Q" + strings.Repeat("I", 8) + "B" + "QQQQ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(0)
+			defer e.Close()
+
+			var mu sync.Mutex
+			var ran strings.Builder
+			record := func(name string) {
+				mu.Lock()
+				ran.WriteString(name)
+				mu.Unlock()
+			}
+			release := block(t, e)
+			var wg sync.WaitGroup
+			for i := 0; i < len(tt.queued); i++ {
+				enqueue(t, e, &wg, lanes[tt.queued[i]], tt.queued[i:i+1], record)
+			}
+			release()
+			wg.Wait()
+			if got := ran.String(); got != tt.want {
+				t.Errorf("ran %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutorCancellation(t *testing.T) {
+	tests := []struct {
+		name string
+		// cancel ends the job's context while it is queued behind a blocking
+		// job, or leaves it to the 20ms deadline.
+		cancel func(cancel context.CancelFunc)
+	}{
+		{"cancelled while queued", func(cancel context.CancelFunc) { cancel() }},
+		{"deadline passes while queued", func(context.CancelFunc) {}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor(0)
+			defer e.Close()
+			release := block(t, e)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			ctx, timing := WithTiming(ctx)
+			ran := false
+			errs := make(chan error, 1)
+			go func() {
+				_, err := e.Submit(ctx, LaneQuery, func(context.Context) (any, error) {
+					ran = true
+					return nil, nil
+				})
+				errs <- err
+			}()
+			waitFor(t, "job to queue", func() bool { return e.Depth(LaneQuery) == 1 })
+			tt.cancel(cancel)
+
+			err := <-errs
+			if ctx.Err() == nil || !errors.Is(err, ctx.Err()) {
+				t.Fatalf("Submit error = %v, want the context's error", err)
+			}
+			if depth := e.Depth(LaneQuery); depth != 0 {
+				t.Errorf("cancelled job still counts towards depth: %d", depth)
+			}
+			release()
+			// A job queued after the cancelled one proves the executor moved on.
+			if _, err := e.Submit(context.Background(), LaneQuery, func(context.Context) (any, error) { return nil, nil }); err != nil {
+				t.Fatal(err)
+			}
+			if ran {
+				t.Error("cancelled job ran")
+			}
+			if _, _, jobs := timing.Totals(); jobs != 1 {
+				t.Errorf("timing recorded %d jobs, want the withdrawn one", jobs)
+			}
+		})
+	}
+}
+
+func TestExecutorQueueFull(t *testing.T) {
+	e := NewExecutor(2)
+	defer e.Close()
+	release := block(t, e)
+	defer func() { release() }()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		enqueue(t, e, &wg, LaneQuery, fmt.Sprint(i), func(string) {})
+	}
+	ctx, timing := WithTiming(context.Background())
+	_, err := e.Submit(ctx, LaneQuery, func(context.Context) (any, error) {
+		t.Error("rejected job ran")
+		return nil, nil
+	})
+	var full *QueueFullError
+	if !errors.As(err, &full) {
+		t.Fatalf("Submit error = %v, want a QueueFullError", err)
+	}
+	if full.Lane != LaneQuery || full.Depth != 2 {
+		t.Errorf("rejection = %+v, want the query lane at depth 2", full)
+	}
+	if full.RetryAfter < minRetryAfter || full.RetryAfter > maxRetryAfter {
+		t.Errorf("RetryAfter = %s, want within [%s, %s]", full.RetryAfter, minRetryAfter, maxRetryAfter)
+	}
+	if timing.Rejected() != full {
+		t.Error("timing did not record the rejection")
+	}
+
+	// A request that already ran a job has had effects, so its rejection is
+	// not reported as retryable.
+	ctx, timing = WithTiming(context.Background())
+	release()
+	waitFor(t, "queue to drain", func() bool { return e.Depth(LaneQuery) == 0 })
+	if _, err := e.Submit(ctx, LaneQuery, func(context.Context) (any, error) { return nil, nil }); err != nil {
+		t.Fatal(err)
+	}
+	release = block(t, e)
+	for i := 0; i < 2; i++ {
+		enqueue(t, e, &wg, LaneQuery, fmt.Sprint(i), func(string) {})
+	}
+	if _, err := e.Submit(ctx, LaneQuery, func(context.Context) (any, error) { return nil, nil }); !errors.As(err, &full) {
+		t.Fatalf("Submit error = %v, want a QueueFullError", err)
+	}
+	if timing.Rejected() != nil {
+		t.Error("rejection after a job ran was reported as retryable")
+	}
+	// Other lanes have their own limit.
+	enqueue(t, e, &wg, LaneInteractive, "I", func(string) {})
+}
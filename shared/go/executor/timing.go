@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Timing accumulates how long one request's executor jobs spent queued and how
+// long they ran, so callers can tell queueing delay from device latency.
+type Timing struct {
+	mu        sync.Mutex
+	queueWait time.Duration
+	run       time.Duration
+	jobs      int
+	started   int
+	rejected  *QueueFullError
+}
+
+type timingKey struct{}
+
+// WithTiming returns a context whose executor jobs are recorded in the returned
+// Timing.
+func WithTiming(ctx context.Context) (context.Context, *Timing) {
+	t := &Timing{}
+	return context.WithValue(ctx, timingKey{}, t), t
+}
+
+func timingFrom(ctx context.Context) *Timing {
+	t, _ := ctx.Value(timingKey{}).(*Timing)
+	return t
+}
+
+// Totals returns the summed queue wait and run time and the number of jobs.
+func (t *Timing) Totals() (queueWait, run time.Duration, jobs int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.queueWait, t.run, t.jobs
+}
+
+// Rejected returns the queue-full rejection that stopped the request before any
+// of its jobs started, if there was one. A rejection after a job ran is left to
+// the caller's own error handling, since the request already had effects.
+func (t *Timing) Rejected() *QueueFullError {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rejected
+}
+
+func (t *Timing) add(wait, run time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.queueWait += wait
+	t.run += run
+	t.jobs++
+}
+
+// begin records that one of the request's jobs is about to run.
+func (t *Timing) begin() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.started++
+}
+
+func (t *Timing) reject(err *QueueFullError) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.rejected == nil && t.started == 0 {
+		t.rejected = err
+	}
+}
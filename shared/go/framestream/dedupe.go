@@ -0,0 +1,48 @@
+package framestream
+
+import (
+	"hash/fnv"
+	"image"
+
+	"github.com/fast-mobile-mcp/shared/imaging"
+)
+
+// Deduper decides whether a capture differs from the last frame sent. With a
+// zero threshold only byte-identical captures are skipped; otherwise frames
+// whose DHash is within threshold bits of the last one are skipped too.
+type Deduper struct {
+	threshold int
+	sent      bool
+	lastSum   uint64
+	lastHash  uint64
+}
+
+func NewDeduper(threshold int) *Deduper {
+	return &Deduper{threshold: threshold}
+}
+
+// Check reports whether raw can be skipped, and records it as the last frame
+// sent when it cannot. The decoded image is returned when hashing needed it so
+// Encode can reuse it.
+func (d *Deduper) Check(raw []byte) (bool, image.Image, error) {
+	sum := fnv.New64a()
+	sum.Write(raw)
+	if d.sent && sum.Sum64() == d.lastSum {
+		return true, nil, nil
+	}
+
+	var img image.Image
+	var hash uint64
+	if d.threshold > 0 {
+		decoded, _, err := imaging.Decode(raw)
+		if err != nil {
+			return false, nil, err
+		}
+		img, hash = decoded, imaging.DHash(decoded)
+		if d.sent && imaging.Distance(hash, d.lastHash) <= d.threshold {
+			return true, img, nil
+		}
+	}
+	d.sent, d.lastSum, d.lastHash = true, sum.Sum64(), hash
+	return false, img, nil
+}
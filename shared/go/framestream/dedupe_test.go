@@ -0,0 +1,79 @@
+package framestream
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// gradient is a horizontal ramp, with the pixels at marks painted white.
+func gradient(t *testing.T, reverse bool, marks ...image.Point) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			v := uint8(x * 4)
+			if reverse {
+				v = 255 - v
+			}
+			img.Set(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+	for _, p := range marks {
+		img.Set(p.X, p.Y, color.White)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDeduperCheck(t *testing.T) {
+	base := gradient(t, false)
+	speck := gradient(t, false, image.Pt(10, 10))
+	reversed := gradient(t, true)
+
+	tests := []struct {
+		name      string
+		threshold int
+		frames    [][]byte
+		skipped   []bool
+	}{
+		{"first frame is sent", 0, [][]byte{base}, []bool{false}},
+		{"identical bytes are skipped", 0, [][]byte{base, base}, []bool{false, true}},
+		{"exact mode sends any change", 0, [][]byte{base, speck}, []bool{false, false}},
+		{"small change within threshold is skipped", 4, [][]byte{base, speck}, []bool{false, true}},
+		{"large change beyond threshold is sent", 4, [][]byte{base, reversed}, []bool{false, false}},
+		{"compares against last sent frame", 4, [][]byte{base, reversed, base}, []bool{false, false, false}},
+		{"skipped frames do not move the baseline", 4, [][]byte{base, speck, base}, []bool{false, true, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDeduper(tt.threshold)
+			for i, frame := range tt.frames {
+				skip, img, err := d.Check(frame)
+				if err != nil {
+					t.Fatalf("frame %d: %v", i, err)
+				}
+				if skip != tt.skipped[i] {
+					t.Errorf("frame %d: skipped = %v, want %v", i, skip, tt.skipped[i])
+				}
+				if tt.threshold > 0 && img == nil && !bytes.Equal(frame, tt.frames[0]) {
+					t.Errorf("frame %d: decoded image not returned for reuse", i)
+				}
+			}
+		})
+	}
+}
+
+func TestDeduperRejectsUndecodableFrameWhenHashing(t *testing.T) {
+	if _, _, err := NewDeduper(4).Check([]byte("not an image")); err == nil {
+		t.Fatal("expected a decode error")
+	}
+	if skip, _, err := NewDeduper(0).Check([]byte("not an image")); err != nil || skip {
+		t.Fatalf("exact mode: skip = %v, err = %v; want a sent frame", skip, err)
+	}
+}
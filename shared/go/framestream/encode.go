@@ -0,0 +1,64 @@
+// Package framestream captures, de-duplicates, paces and encodes the frames a
+// worker sends on ScreenshotStream. It knows nothing about the device: the
+// worker supplies the capture function.
+package framestream
+
+import (
+	"bytes"
+	"image"
+	"time"
+
+	"github.com/fast-mobile-mcp/shared/imaging"
+)
+
+// HeartbeatInterval is how often streams report their health, including
+// effective FPS.
+const HeartbeatInterval = time.Second
+
+// Image is an encoded frame. SourceWidth is the width of the capture it was
+// made from, so downsizing can be folded into the frame's scale.
+type Image struct {
+	Data        []byte
+	Format      imaging.Format
+	Width       int
+	Height      int
+	SourceWidth int
+}
+
+// Encode prepares one frame. With no quality or size limits the device image
+// is sent as captured; otherwise it is scaled to fit and re-encoded as JPEG.
+// img is raw already decoded, or nil.
+func Encode(raw []byte, img image.Image, quality, maxWidth, maxHeight int) (Image, error) {
+	if quality == 0 && maxWidth == 0 && maxHeight == 0 {
+		cfg, name, err := image.DecodeConfig(bytes.NewReader(raw))
+		if err != nil {
+			return Image{}, err
+		}
+		return Image{Data: raw, Format: imaging.Format(name), Width: cfg.Width, Height: cfg.Height, SourceWidth: cfg.Width}, nil
+	}
+
+	if img == nil {
+		decoded, _, err := imaging.Decode(raw)
+		if err != nil {
+			return Image{}, err
+		}
+		img = decoded
+	}
+	sourceWidth := img.Bounds().Dx()
+	img = imaging.Fit(img, maxWidth, maxHeight)
+	data, err := imaging.Encode(img, imaging.JPEG, quality)
+	if err != nil {
+		return Image{}, err
+	}
+	b := img.Bounds()
+	return Image{Data: data, Format: imaging.JPEG, Width: b.Dx(), Height: b.Dy(), SourceWidth: sourceWidth}, nil
+}
+
+// Scale is the number of frame pixels per tap coordinate unit, given the
+// device's own pixels per unit.
+func (i Image) Scale(deviceScale float64) float32 {
+	if i.SourceWidth == 0 {
+		return float32(deviceScale)
+	}
+	return float32(deviceScale * float64(i.Width) / float64(i.SourceWidth))
+}
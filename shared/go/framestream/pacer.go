@@ -0,0 +1,50 @@
+package framestream
+
+import (
+	"sync"
+	"time"
+)
+
+// Pacer adapts the capture interval to what the device and the consumer
+// sustain: never faster than the requested FPS, and no faster than smoothed
+// capture or send times allow.
+type Pacer struct {
+	mu      sync.Mutex
+	floor   time.Duration
+	capture time.Duration
+	send    time.Duration
+}
+
+// pacerSmoothing weights each new observation in the moving averages.
+const pacerSmoothing = 0.3
+
+// NewPacer returns a pacer that never goes below floor, the interval of the
+// requested FPS.
+func NewPacer(floor time.Duration) *Pacer {
+	return &Pacer{floor: floor}
+}
+
+func (p *Pacer) ObserveCapture(d time.Duration) {
+	p.mu.Lock()
+	p.capture = smooth(p.capture, d)
+	p.mu.Unlock()
+}
+
+func (p *Pacer) ObserveSend(d time.Duration) {
+	p.mu.Lock()
+	p.send = smooth(p.send, d)
+	p.mu.Unlock()
+}
+
+func (p *Pacer) Interval() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return max(p.floor, p.capture, p.send)
+}
+
+func smooth(avg, sample time.Duration) time.Duration {
+	if avg == 0 {
+		return sample
+	}
+	return time.Duration(pacerSmoothing*float64(sample) + (1-pacerSmoothing)*float64(avg))
+}
@@ -0,0 +1,39 @@
+package framestream
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPacerInterval(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name     string
+		floor    time.Duration
+		captures []time.Duration
+		sends    []time.Duration
+		want     time.Duration
+	}{
+		{"floor with no observations", 100 * ms, nil, nil, 100 * ms},
+		{"fast device keeps the floor", 100 * ms, []time.Duration{20 * ms, 30 * ms}, []time.Duration{5 * ms}, 100 * ms},
+		{"first capture is taken as is", 50 * ms, []time.Duration{200 * ms}, nil, 200 * ms},
+		{"capture is smoothed", 50 * ms, []time.Duration{100 * ms, 200 * ms}, nil, 130 * ms},
+		{"spike decays", 50 * ms, []time.Duration{100 * ms, 1000 * ms, 100 * ms, 100 * ms}, nil, 232300 * time.Microsecond},
+		{"slow consumer sets the pace", 50 * ms, []time.Duration{60 * ms}, []time.Duration{300 * ms}, 300 * ms},
+		{"send is smoothed", 50 * ms, nil, []time.Duration{400 * ms, 100 * ms}, 310 * ms},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPacer(tt.floor)
+			for _, d := range tt.captures {
+				p.ObserveCapture(d)
+			}
+			for _, d := range tt.sends {
+				p.ObserveSend(d)
+			}
+			if got := p.Interval(); got != tt.want {
+				t.Errorf("Interval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
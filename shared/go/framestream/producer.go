@@ -0,0 +1,131 @@
+package framestream
+
+import (
+	"context"
+	"errors"
+	"image"
+	"sync/atomic"
+	"time"
+)
+
+// ErrBusy is returned by a capture function when the device had no room for
+// the capture, such as when its executor lane is full. The producer counts the
+// tick as dropped and tries again on the next one; the capture function should
+// already have waited as long as the device asked.
+var ErrBusy = errors.New("device busy")
+
+// Frame is an encoded frame waiting to be sent.
+type Frame struct {
+	Image
+	CapturedAt time.Time
+}
+
+// Producer captures and encodes frames on its own goroutine. Frames holds at
+// most the newest frame: when the sender has not taken the previous one it is
+// replaced and counted as dropped.
+type Producer struct {
+	pacer   *Pacer
+	dedupe  *Deduper
+	capture func(context.Context) ([]byte, error)
+	encode  func([]byte, image.Image) (Image, error)
+	frames  chan Frame
+	errs    chan error
+	skipped atomic.Uint32
+	dropped atomic.Uint32
+}
+
+// NewProducer paces capture with pacer and prepares each capture with encode.
+// dedupe may be nil to send every capture.
+func NewProducer(pacer *Pacer, dedupe *Deduper, capture func(context.Context) ([]byte, error), encode func([]byte, image.Image) (Image, error)) *Producer {
+	return &Producer{
+		pacer:   pacer,
+		dedupe:  dedupe,
+		capture: capture,
+		encode:  encode,
+		frames:  make(chan Frame, 1),
+		errs:    make(chan error, 1),
+	}
+}
+
+// Frames delivers the newest frame not yet taken.
+func (p *Producer) Frames() <-chan Frame { return p.frames }
+
+// Errs delivers the error that stopped Run, if any.
+func (p *Producer) Errs() <-chan error { return p.errs }
+
+// Skipped counts captures the deduper found unchanged.
+func (p *Producer) Skipped() uint32 { return p.skipped.Load() }
+
+// Dropped counts frames replaced before the sender took them and ticks the
+// device was too busy to capture.
+func (p *Producer) Dropped() uint32 { return p.dropped.Load() }
+
+// Pacer returns the pacer, so the sender can report its send times.
+func (p *Producer) Pacer() *Pacer { return p.pacer }
+
+// Run captures until ctx is done or a capture or encode fails.
+func (p *Producer) Run(ctx context.Context) {
+	next := time.Now()
+	for {
+		if wait := time.Until(next); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+		start := time.Now()
+		frame, ok, err := p.produce(ctx, start)
+		if errors.Is(err, ErrBusy) && ctx.Err() == nil {
+			p.dropped.Add(1)
+			next = start.Add(p.pacer.Interval())
+			continue
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				p.errs <- err
+			}
+			return
+		}
+		p.pacer.ObserveCapture(time.Since(start))
+		next = start.Add(p.pacer.Interval())
+		if !ok {
+			continue
+		}
+
+		select {
+		case p.frames <- frame:
+		default:
+			select {
+			case <-p.frames:
+				p.dropped.Add(1)
+			default:
+			}
+			p.frames <- frame
+		}
+	}
+}
+
+func (p *Producer) produce(ctx context.Context, capturedAt time.Time) (Frame, bool, error) {
+	raw, err := p.capture(ctx)
+	if err != nil {
+		return Frame{}, false, err
+	}
+	var decoded image.Image
+	if p.dedupe != nil {
+		skip, img, err := p.dedupe.Check(raw)
+		if err != nil {
+			return Frame{}, false, err
+		}
+		if skip {
+			p.skipped.Add(1)
+			return Frame{}, false, nil
+		}
+		decoded = img
+	}
+	encoded, err := p.encode(raw, decoded)
+	if err != nil {
+		return Frame{}, false, err
+	}
+	return Frame{Image: encoded, CapturedAt: capturedAt.UTC()}, true, nil
+}
@@ -0,0 +1,57 @@
+package framestream
+
+import (
+	"context"
+	"errors"
+	"image"
+	"testing"
+	"time"
+)
+
+func TestProducerCaptureErrors(t *testing.T) {
+	failure := errors.New("screenshot failed")
+	tests := []struct {
+		name        string
+		errs        []error
+		wantFrame   bool
+		wantDropped uint32
+		wantErr     error
+	}{
+		{"busy ticks are dropped and retried", []error{ErrBusy, ErrBusy, nil}, true, 2, nil},
+		{"other errors end the producer", []error{failure}, false, 0, failure},
+		{"an error after busy ticks still ends it", []error{ErrBusy, failure}, false, 1, failure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			calls := 0
+			capture := func(context.Context) ([]byte, error) {
+				err := tt.errs[min(calls, len(tt.errs)-1)]
+				calls++
+				return []byte("frame"), err
+			}
+			encode := func(raw []byte, _ image.Image) (Image, error) {
+				return Image{Data: raw}, nil
+			}
+			p := NewProducer(NewPacer(time.Millisecond), nil, capture, encode)
+			go p.Run(ctx)
+
+			select {
+			case frame := <-p.Frames():
+				if !tt.wantFrame {
+					t.Fatalf("unexpected frame %q", frame.Data)
+				}
+			case err := <-p.Errs():
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("producer neither sent a frame nor failed")
+			}
+			if got := p.Dropped(); got != tt.wantDropped {
+				t.Errorf("Dropped() = %d, want %d", got, tt.wantDropped)
+			}
+		})
+	}
+}
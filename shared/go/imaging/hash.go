@@ -0,0 +1,34 @@
+package imaging
+
+import (
+	"image"
+	"math/bits"
+)
+
+// DHash is a 64-bit difference hash: the image is reduced to 9x8 grayscale and
+// each bit records whether a pixel is brighter than its right neighbour. Small
+// visual changes flip few bits, so Distance measures how different two frames
+// look.
+func DHash(img image.Image) uint64 {
+	small := boxResize(toRGBA(img), 9, 8)
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		row := small.Pix[y*small.Stride:]
+		for x := 0; x < 8; x++ {
+			if luma(row[x*4:]) > luma(row[(x+1)*4:]) {
+				hash |= 1 << uint(y*8+x)
+			}
+		}
+	}
+	return hash
+}
+
+// Distance is the number of differing bits between two hashes, from 0 for
+// identical frames to 64.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+func luma(p []uint8) uint32 {
+	return (299*uint32(p[0]) + 587*uint32(p[1]) + 114*uint32(p[2])) / 1000
+}
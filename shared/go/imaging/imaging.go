@@ -0,0 +1,81 @@
+// Package imaging decodes, crops and re-encodes device screenshots for the
+// workers. It only depends on the standard library codecs.
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+)
+
+// Format is an encoded image format.
+type Format string
+
+const (
+	PNG  Format = "png"
+	JPEG Format = "jpeg"
+)
+
+const DefaultJPEGQuality = 80
+
+// MimeType returns the media type for f.
+func (f Format) MimeType() string {
+	return "image/" + string(f)
+}
+
+// Decode parses PNG or JPEG data and reports which of the two it was.
+func Decode(data []byte) (image.Image, Format, error) {
+	img, name, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decode screenshot: %w", err)
+	}
+	return img, Format(name), nil
+}
+
+// Encode writes img as f. quality applies to JPEG only; zero selects
+// DefaultJPEGQuality.
+func Encode(img image.Image, f Format, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	switch f {
+	case PNG:
+		enc := png.Encoder{CompressionLevel: png.BestSpeed}
+		if err := enc.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+	case JPEG:
+		if quality <= 0 || quality > 100 {
+			quality = DefaultJPEGQuality
+		}
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported image format %q", f)
+	}
+	return buf.Bytes(), nil
+}
+
+type subImager interface {
+	SubImage(r image.Rectangle) image.Image
+}
+
+// Crop returns the part of img inside r, clipped to the image. It shares pixels
+// with img rather than copying them.
+func Crop(img image.Image, r image.Rectangle) (image.Image, error) {
+	r = r.Intersect(img.Bounds())
+	if r.Empty() {
+		return nil, fmt.Errorf("crop region is outside the %dx%d image", img.Bounds().Dx(), img.Bounds().Dy())
+	}
+	if s, ok := img.(subImager); ok {
+		return s.SubImage(r), nil
+	}
+	out := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			out.Set(x-r.Min.X, y-r.Min.Y, img.At(x, y))
+		}
+	}
+	return out, nil
+}
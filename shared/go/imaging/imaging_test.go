@@ -0,0 +1,169 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// solid returns a w by h image filled with c.
+func solid(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+func TestCrop(t *testing.T) {
+	img := solid(100, 50, color.RGBA{10, 20, 30, 255})
+	tests := []struct {
+		name    string
+		region  image.Rectangle
+		want    image.Rectangle
+		wantErr bool
+	}{
+		{"inside", image.Rect(10, 5, 40, 25), image.Rect(10, 5, 40, 25), false},
+		{"whole image", image.Rect(0, 0, 100, 50), image.Rect(0, 0, 100, 50), false},
+		{"partly outside is clipped", image.Rect(80, 40, 150, 90), image.Rect(80, 40, 100, 50), false},
+		{"negative origin is clipped", image.Rect(-10, -10, 20, 20), image.Rect(0, 0, 20, 20), false},
+		{"fully outside", image.Rect(200, 200, 300, 300), image.Rectangle{}, true},
+		{"empty", image.Rect(10, 10, 10, 30), image.Rectangle{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Crop(img, tt.region)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Crop(%v) = %v, want an error", tt.region, out.Bounds())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.Bounds() != tt.want {
+				t.Errorf("Crop(%v) bounds = %v, want %v", tt.region, out.Bounds(), tt.want)
+			}
+		})
+	}
+}
+
+// plainImage hides the SubImage method so Crop has to copy pixels.
+type plainImage struct{ image.Image }
+
+func TestCropCopiesImagesWithoutSubImage(t *testing.T) {
+	img := solid(20, 20, color.RGBA{200, 100, 50, 255})
+	out, err := Crop(plainImage{img}, image.Rect(5, 5, 15, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := out.Bounds(); got != image.Rect(0, 0, 10, 5) {
+		t.Errorf("bounds = %v, want 10x5 at the origin", got)
+	}
+	if got := color.RGBAModel.Convert(out.At(3, 3)); got != (color.RGBA{200, 100, 50, 255}) {
+		t.Errorf("pixel = %v, want the source colour", got)
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	img := solid(32, 16, color.RGBA{40, 120, 200, 255})
+	tests := []struct {
+		format   Format
+		mimeType string
+		// tolerance is how far a channel may drift through lossy encoding.
+		tolerance int
+	}{
+		{PNG, "image/png", 0},
+		{JPEG, "image/jpeg", 8},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			if got := tt.format.MimeType(); got != tt.mimeType {
+				t.Errorf("MimeType() = %q, want %q", got, tt.mimeType)
+			}
+			data, err := Encode(img, tt.format, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, format, err := Decode(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != tt.format {
+				t.Errorf("decoded format = %q, want %q", format, tt.format)
+			}
+			if out.Bounds() != img.Bounds() {
+				t.Errorf("decoded bounds = %v, want %v", out.Bounds(), img.Bounds())
+			}
+			got := color.RGBAModel.Convert(out.At(16, 8)).(color.RGBA)
+			want := img.RGBAAt(16, 8)
+			for i, pair := range [][2]uint8{{got.R, want.R}, {got.G, want.G}, {got.B, want.B}} {
+				if d := int(pair[0]) - int(pair[1]); d > tt.tolerance || -d > tt.tolerance {
+					t.Errorf("channel %d = %d, want %d±%d", i, pair[0], pair[1], tt.tolerance)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeRejectsUnknownFormat(t *testing.T) {
+	if _, err := Encode(solid(2, 2, color.RGBA{}), Format("gif"), 0); err == nil {
+		t.Fatal("expected an error for an unsupported format")
+	}
+}
+
+func TestDecodeRejectsGarbage(t *testing.T) {
+	if _, _, err := Decode([]byte("not an image")); err == nil {
+		t.Fatal("expected a decode error")
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		name                string
+		width, height       int
+		maxWidth, maxHeight int
+		want                image.Point
+		unchanged           bool
+	}{
+		{"only max width keeps aspect ratio", 1080, 2400, 540, 0, image.Pt(540, 1200), false},
+		{"only max height keeps aspect ratio", 1080, 2400, 0, 600, image.Pt(270, 600), false},
+		{"tighter limit wins", 1000, 500, 400, 100, image.Pt(200, 100), false},
+		{"rounds to the nearest pixel", 1000, 333, 500, 0, image.Pt(500, 167), false},
+		{"already small enough", 300, 200, 400, 400, image.Pt(300, 200), true},
+		{"exactly at the limit", 400, 200, 400, 200, image.Pt(400, 200), true},
+		{"no limits", 300, 200, 0, 0, image.Pt(300, 200), true},
+		{"never smaller than one pixel", 1000, 2, 10, 0, image.Pt(10, 1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := solid(tt.width, tt.height, color.RGBA{90, 90, 90, 255})
+			out := Fit(img, tt.maxWidth, tt.maxHeight)
+			if got := out.Bounds().Size(); got != tt.want {
+				t.Errorf("Fit size = %v, want %v", got, tt.want)
+			}
+			if same := out == image.Image(img); same != tt.unchanged {
+				t.Errorf("returned the input image = %v, want %v", same, tt.unchanged)
+			}
+		})
+	}
+}
+
+func TestFitAveragesPixels(t *testing.T) {
+	// Alternating black and white columns average to mid grey.
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			v := uint8(0)
+			if x%2 == 1 {
+				v = 254
+			}
+			img.SetRGBA(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+	out := Fit(img, 2, 0).(*image.RGBA)
+	if got := out.RGBAAt(0, 0); got != (color.RGBA{127, 127, 127, 255}) {
+		t.Errorf("pixel = %v, want mid grey", got)
+	}
+}
@@ -0,0 +1,103 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// Mark is a numbered box drawn over a screenshot for set-of-marks prompting.
+type Mark struct {
+	Label string
+	Rect  image.Rectangle
+}
+
+// markPalette cycles so neighbouring boxes are easy to tell apart.
+var markPalette = []color.RGBA{
+	{230, 25, 75, 255},
+	{60, 180, 75, 255},
+	{0, 130, 200, 255},
+	{245, 130, 48, 255},
+	{145, 30, 180, 255},
+	{0, 128, 128, 255},
+}
+
+// digitGlyphs is a 5x7 bitmap font for 0-9; each row uses the low five bits.
+var digitGlyphs = [10][7]uint8{
+	{0x0E, 0x11, 0x13, 0x15, 0x19, 0x11, 0x0E},
+	{0x04, 0x0C, 0x04, 0x04, 0x04, 0x04, 0x0E},
+	{0x0E, 0x11, 0x01, 0x02, 0x04, 0x08, 0x1F},
+	{0x1F, 0x02, 0x04, 0x02, 0x01, 0x11, 0x0E},
+	{0x02, 0x06, 0x0A, 0x12, 0x1F, 0x02, 0x02},
+	{0x1F, 0x10, 0x1E, 0x01, 0x01, 0x11, 0x0E},
+	{0x06, 0x08, 0x10, 0x1E, 0x11, 0x11, 0x0E},
+	{0x1F, 0x01, 0x02, 0x04, 0x08, 0x08, 0x08},
+	{0x0E, 0x11, 0x11, 0x0E, 0x11, 0x11, 0x0E},
+	{0x0E, 0x11, 0x11, 0x0F, 0x01, 0x02, 0x0C},
+}
+
+// DrawMarks returns a copy of img with an outlined box and a filled label tag
+// for each mark. Labels may only contain digits; other runes are skipped.
+func DrawMarks(img image.Image, marks []Mark) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(out, out.Rect, img, b.Min, draw.Src)
+
+	// Glyph pixels scale with the image so labels stay legible on both
+	// downscaled frames and full-resolution captures.
+	scale := max(1, b.Dx()/360)
+	thickness := max(1, scale)
+	white := image.NewUniform(color.RGBA{255, 255, 255, 255})
+
+	for i, mark := range marks {
+		r := mark.Rect.Intersect(out.Rect)
+		if r.Empty() {
+			continue
+		}
+		fill := image.NewUniform(markPalette[i%len(markPalette)])
+		for t := 0; t < thickness; t++ {
+			inner := r.Inset(t)
+			if inner.Empty() {
+				break
+			}
+			draw.Draw(out, image.Rect(inner.Min.X, inner.Min.Y, inner.Max.X, inner.Min.Y+1), fill, image.Point{}, draw.Src)
+			draw.Draw(out, image.Rect(inner.Min.X, inner.Max.Y-1, inner.Max.X, inner.Max.Y), fill, image.Point{}, draw.Src)
+			draw.Draw(out, image.Rect(inner.Min.X, inner.Min.Y, inner.Min.X+1, inner.Max.Y), fill, image.Point{}, draw.Src)
+			draw.Draw(out, image.Rect(inner.Max.X-1, inner.Min.Y, inner.Max.X, inner.Max.Y), fill, image.Point{}, draw.Src)
+		}
+
+		digits := make([]int, 0, len(mark.Label))
+		for _, c := range mark.Label {
+			if c >= '0' && c <= '9' {
+				digits = append(digits, int(c-'0'))
+			}
+		}
+		if len(digits) == 0 {
+			continue
+		}
+		pad := scale
+		tag := image.Rect(0, 0, len(digits)*6*scale-scale+2*pad, 7*scale+2*pad).Add(r.Min)
+		// Keep the tag on screen when the box hugs the right or bottom edge.
+		if tag.Max.X > out.Rect.Max.X {
+			tag = tag.Sub(image.Pt(tag.Max.X-out.Rect.Max.X, 0))
+		}
+		if tag.Max.Y > out.Rect.Max.Y {
+			tag = tag.Sub(image.Pt(0, tag.Max.Y-out.Rect.Max.Y))
+		}
+		draw.Draw(out, tag, fill, image.Point{}, draw.Src)
+		for n, d := range digits {
+			originX := tag.Min.X + pad + n*6*scale
+			originY := tag.Min.Y + pad
+			for row, bitsRow := range digitGlyphs[d] {
+				for col := 0; col < 5; col++ {
+					if bitsRow&(0x10>>col) == 0 {
+						continue
+					}
+					px := image.Rect(0, 0, scale, scale).Add(image.Pt(originX+col*scale, originY+row*scale))
+					draw.Draw(out, px, white, image.Point{}, draw.Src)
+				}
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestDrawMarks(t *testing.T) {
+	background := color.RGBA{0, 0, 0, 255}
+	tests := []struct {
+		name  string
+		mark  Mark
+		box   image.Point // a point on the box outline
+		label image.Point // a point inside the label tag
+	}{
+		{"inside", Mark{Label: "7", Rect: image.Rect(20, 20, 60, 50)}, image.Pt(40, 49), image.Pt(21, 21)},
+		{"partly off the right and bottom edges", Mark{Label: "12", Rect: image.Rect(70, 70, 140, 140)}, image.Pt(70, 90), image.Pt(99, 99)},
+		{"partly off the top left", Mark{Label: "3", Rect: image.Rect(-30, -30, 30, 30)}, image.Pt(29, 20), image.Pt(1, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := solid(100, 100, background)
+			out := DrawMarks(img, []Mark{tt.mark})
+			if out.Bounds() != img.Bounds() {
+				t.Fatalf("bounds = %v, want %v", out.Bounds(), img.Bounds())
+			}
+			if got := out.RGBAAt(tt.box.X, tt.box.Y); got != markPalette[0] {
+				t.Errorf("box pixel %v = %v, want %v", tt.box, got, markPalette[0])
+			}
+			if got := out.RGBAAt(tt.label.X, tt.label.Y); got == background {
+				t.Errorf("label pixel %v unchanged", tt.label)
+			}
+			if got := out.RGBAAt(95, 5); got != background {
+				t.Errorf("pixel away from the mark changed to %v", got)
+			}
+			if got := img.RGBAAt(tt.box.X, tt.box.Y); got != background {
+				t.Error("DrawMarks modified its input")
+			}
+		})
+	}
+}
+
+func TestDrawMarksLabelGlyphs(t *testing.T) {
+	background := color.RGBA{0, 0, 0, 255}
+	mark := func(label string) *image.RGBA {
+		return DrawMarks(solid(60, 60, background), []Mark{{Label: label, Rect: image.Rect(10, 10, 50, 50)}})
+	}
+	// The tag is one glyph cell wide per digit, so a longer label covers more
+	// of the box, and different digits light different pixels.
+	whites := func(img *image.RGBA) (n int) {
+		for y := 10; y < 20; y++ {
+			for x := 10; x < 30; x++ {
+				if img.RGBAAt(x, y) == (color.RGBA{255, 255, 255, 255}) {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	one, eight, eighteen := whites(mark("1")), whites(mark("8")), whites(mark("18"))
+	if one == 0 || eight == 0 {
+		t.Fatalf("labels drew no glyph pixels: 1=%d 8=%d", one, eight)
+	}
+	if one == eight {
+		t.Errorf("digits 1 and 8 drew the same number of pixels (%d)", one)
+	}
+	if eighteen != one+eight {
+		t.Errorf("label 18 drew %d pixels, want 1 and 8 together (%d)", eighteen, one+eight)
+	}
+	if whites(mark("n-")) != 0 {
+		t.Error("a label without digits drew glyphs")
+	}
+}
+
+func TestDrawMarksSkipsMarksOffScreen(t *testing.T) {
+	img := solid(50, 50, color.RGBA{0, 0, 0, 255})
+	out := DrawMarks(img, []Mark{{Label: "1", Rect: image.Rect(100, 100, 150, 150)}})
+	for i := range out.Pix {
+		if out.Pix[i] != img.Pix[i] {
+			t.Fatal("a mark entirely off screen changed the image")
+		}
+	}
+}
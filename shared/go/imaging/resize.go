@@ -0,0 +1,87 @@
+package imaging
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Fit scales img down, keeping its aspect ratio, so it is at most maxWidth by
+// maxHeight. A zero limit is unbounded. Images that already fit are returned
+// unchanged; Fit never upscales.
+func Fit(img image.Image, maxWidth, maxHeight int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	scale := 1.0
+	if maxWidth > 0 && w > maxWidth {
+		scale = float64(maxWidth) / float64(w)
+	}
+	if maxHeight > 0 && h > maxHeight {
+		if s := float64(maxHeight) / float64(h); s < scale {
+			scale = s
+		}
+	}
+	if scale >= 1 {
+		return img
+	}
+	dw := max(1, int(float64(w)*scale+0.5))
+	dh := max(1, int(float64(h)*scale+0.5))
+	return boxResize(toRGBA(img), dw, dh)
+}
+
+// toRGBA converts img to a zero-origin RGBA image; draw.Draw has fast paths
+// for the YCbCr and NRGBA images the JPEG and PNG decoders return.
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(out, out.Rect, img, b.Min, draw.Src)
+	return out
+}
+
+// boxResize downscales src by averaging the source pixels that fall in each
+// destination pixel. It reads every source pixel once, which keeps full-size
+// device screenshots well within a frame interval.
+func boxResize(src *image.RGBA, dw, dh int) *image.RGBA {
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	sums := make([]uint32, dw*4)
+	counts := make([]uint32, dw)
+	colStart := make([]int, dw+1)
+	for x := 0; x <= dw; x++ {
+		colStart[x] = x * sw / dw
+	}
+
+	sy := 0
+	for dy := 0; dy < dh; dy++ {
+		rowEnd := (dy + 1) * sh / dh
+		clear(sums)
+		clear(counts)
+		for ; sy < rowEnd; sy++ {
+			row := src.Pix[sy*src.Stride:]
+			for dx := 0; dx < dw; dx++ {
+				for sx := colStart[dx]; sx < colStart[dx+1]; sx++ {
+					p := row[sx*4 : sx*4+4]
+					s := sums[dx*4 : dx*4+4]
+					s[0] += uint32(p[0])
+					s[1] += uint32(p[1])
+					s[2] += uint32(p[2])
+					s[3] += uint32(p[3])
+				}
+				counts[dx] += uint32(colStart[dx+1] - colStart[dx])
+			}
+		}
+		out := dst.Pix[dy*dst.Stride:]
+		for dx := 0; dx < dw; dx++ {
+			n := counts[dx]
+			if n == 0 {
+				continue
+			}
+			for c := 0; c < 4; c++ {
+				out[dx*4+c] = uint8(sums[dx*4+c] / n)
+			}
+		}
+	}
+	return dst
+}
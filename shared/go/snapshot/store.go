@@ -30,14 +30,28 @@ type Node struct {
 	Visible     bool
 	Selected    bool
 	Checked     bool
+	Focused     bool
+}
+
+// Screenshot is a frame captured in the same executor job as a snapshot's
+// nodes, so its pixels and the node bounds describe the same moment.
+type Screenshot struct {
+	Data       []byte
+	MimeType   string
+	Width      int
+	Height     int
+	CapturedAt time.Time
+	// Scale is the screen's pixels per point; zero means one.
+	Scale float64
 }
 
 type Snapshot struct {
-	ID        string
-	DeviceID  string
-	CreatedAt time.Time
-	ExpiresAt time.Time
-	Nodes     []Node
+	ID         string
+	DeviceID   string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	Nodes      []Node
+	Screenshot *Screenshot
 }
 
 type Store struct {
@@ -69,14 +83,21 @@ func (s *Store) Close() {
 }
 
 func (s *Store) Put(deviceID string, nodes []Node) Snapshot {
+	return s.PutWithScreenshot(deviceID, nodes, nil)
+}
+
+// PutWithScreenshot stores nodes together with the screenshot captured
+// alongside them; shot may be nil. Both expire together.
+func (s *Store) PutWithScreenshot(deviceID string, nodes []Node, shot *Screenshot) Snapshot {
 	now := time.Now().UTC()
 	id := fmt.Sprintf("%s-%d", deviceID, now.UnixNano())
 	snap := Snapshot{
-		ID:        id,
-		DeviceID:  deviceID,
-		CreatedAt: now,
-		ExpiresAt: now.Add(s.ttl),
-		Nodes:     append([]Node(nil), nodes...),
+		ID:         id,
+		DeviceID:   deviceID,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(s.ttl),
+		Nodes:      append([]Node(nil), nodes...),
+		Screenshot: shot,
 	}
 
 	s.mu.Lock()
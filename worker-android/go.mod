@@ -7,6 +7,7 @@ require (
 	github.com/fast-mobile-mcp/shared v0.0.0
 	github.com/google/uuid v1.6.0
 	google.golang.org/grpc v1.67.1
+	google.golang.org/protobuf v1.34.2
 )
 
 replace github.com/fast-mobile-mcp/shared => ../shared/go
@@ -0,0 +1,297 @@
+package android
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ADBClient runs adb commands scoped to a single device serial.
+type ADBClient struct {
+	adbPath string
+	serial  string
+
+	displayMu sync.Mutex
+	display   DisplayMetrics
+}
+
+// DisplayMetrics is the screen size in pixels in the device's natural
+// orientation, and its density in dpi.
+type DisplayMetrics struct {
+	Width   int32
+	Height  int32
+	Density int32
+}
+
+func NewADBClient(adbPath, serial string) *ADBClient {
+	return &ADBClient{adbPath: adbPath, serial: serial}
+}
+
+func (c *ADBClient) LaunchApp(ctx context.Context, packageName, activity string, stopExisting bool) error {
+	component := activity
+	if component == "" {
+		resolved, err := c.launcherActivity(ctx, packageName)
+		if err != nil {
+			return err
+		}
+		component = resolved
+	} else if !strings.Contains(component, "/") {
+		component = packageName + "/" + component
+	}
+
+	args := []string{"am", "start", "-W"}
+	if stopExisting {
+		args = append(args, "-S")
+	}
+	args = append(args, "-n", shellQuote(component))
+	out, err := c.Shell(ctx, args...)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(out, "Error:") {
+		return fmt.Errorf("am start failed: %s", strings.TrimSpace(out))
+	}
+	return nil
+}
+
+func (c *ADBClient) TerminateApp(ctx context.Context, packageName string) error {
+	_, err := c.Shell(ctx, "am", "force-stop", shellQuote(packageName))
+	return err
+}
+
+func (c *ADBClient) InstallApp(ctx context.Context, apkPath string) error {
+	out, err := c.run(ctx, "install", "-r", apkPath)
+	if err != nil {
+		return err
+	}
+	return expectSuccess("install", out)
+}
+
+func (c *ADBClient) UninstallApp(ctx context.Context, packageName string) error {
+	out, err := c.run(ctx, "uninstall", packageName)
+	if err != nil {
+		return err
+	}
+	return expectSuccess("uninstall", out)
+}
+
+func (c *ADBClient) ClearAppData(ctx context.Context, packageName string) error {
+	out, err := c.Shell(ctx, "pm", "clear", shellQuote(packageName))
+	if err != nil {
+		return err
+	}
+	return expectSuccess("pm clear", out)
+}
+
+// OpenURL fires a VIEW intent for url, restricted to packageName when set so a
+// link that several apps can handle does not stop at the chooser.
+func (c *ADBClient) OpenURL(ctx context.Context, url, packageName string) error {
+	args := []string{"am", "start", "-W", "-a", "android.intent.action.VIEW", "-d", shellQuote(url)}
+	if packageName != "" {
+		args = append(args, shellQuote(packageName))
+	}
+	out, err := c.Shell(ctx, args...)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(out, "Error:") {
+		return fmt.Errorf("am start failed: %s", strings.TrimSpace(out))
+	}
+	return nil
+}
+
+// SetLocation sends `geo fix` to the emulator console. The console takes
+// longitude before latitude and only exists on emulators.
+func (c *ADBClient) SetLocation(ctx context.Context, lat, lon, alt float64) error {
+	out, err := c.run(ctx, "emu", "geo", "fix", formatCoord(lon), formatCoord(lat), formatCoord(alt))
+	if err != nil {
+		return err
+	}
+	if strings.Contains(out, "KO") {
+		return fmt.Errorf("geo fix rejected: %s", strings.TrimSpace(out))
+	}
+	return nil
+}
+
+func (c *ADBClient) GrantPermission(ctx context.Context, packageName, permission string) error {
+	_, err := c.Shell(ctx, "pm", "grant", shellQuote(packageName), permission)
+	return err
+}
+
+func (c *ADBClient) RevokePermission(ctx context.Context, packageName, permission string) error {
+	_, err := c.Shell(ctx, "pm", "revoke", shellQuote(packageName), permission)
+	return err
+}
+
+// ClearPermissionFlags drops the user-set and user-fixed flags so the app asks
+// for permission again instead of treating an earlier denial as final.
+func (c *ADBClient) ClearPermissionFlags(ctx context.Context, packageName, permission string) error {
+	_, err := c.Shell(ctx, "pm", "clear-permission-flags", shellQuote(packageName), permission, "user-set", "user-fixed")
+	return err
+}
+
+// ShellResult is the outcome of ExecShell.
+type ShellResult struct {
+	ExitCode  int
+	Stdout    string
+	Stderr    string
+	Truncated bool
+}
+
+// ExecShell runs argv on the device with every argument quoted, so shell
+// metacharacters in caller input are passed through literally. Output beyond
+// maxBytes per stream is dropped and reported as truncated.
+func (c *ADBClient) ExecShell(ctx context.Context, argv []string, maxBytes int) (ShellResult, error) {
+	quoted := make([]string, 0, len(argv))
+	for _, arg := range argv {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	stdout := &cappedBuffer{max: maxBytes}
+	stderr := &cappedBuffer{max: maxBytes}
+	cmd := exec.CommandContext(ctx, c.adbPath, "-s", c.serial, "shell", strings.Join(quoted, " "))
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	result := ShellResult{
+		Stdout:    stdout.String(),
+		Stderr:    stderr.String(),
+		Truncated: stdout.truncated || stderr.truncated,
+	}
+	// A timeout kills adb, which surfaces as an exit error; report the deadline
+	// instead so callers do not mistake it for the command's own status.
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+		return result, fmt.Errorf("shell command stopped: %w", ctxErr)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// adb forwards the remote exit status with the shell v2 protocol.
+		result.ExitCode = exitErr.ExitCode()
+		return result, nil
+	}
+	return result, err
+}
+
+// DisplayMetrics reads `wm size` and `wm density`, preferring override values.
+// They are fixed for a session, so they are read once.
+func (c *ADBClient) DisplayMetrics(ctx context.Context) (DisplayMetrics, error) {
+	c.displayMu.Lock()
+	defer c.displayMu.Unlock()
+	if c.display.Width > 0 {
+		return c.display, nil
+	}
+
+	sizeOut, err := c.Shell(ctx, "wm", "size")
+	if err != nil {
+		return DisplayMetrics{}, err
+	}
+	size := wmValue(sizeOut)
+	w, h, ok := strings.Cut(size, "x")
+	width, werr := strconv.Atoi(w)
+	height, herr := strconv.Atoi(h)
+	if !ok || werr != nil || herr != nil {
+		return DisplayMetrics{}, fmt.Errorf("unexpected wm size output: %q", strings.TrimSpace(sizeOut))
+	}
+
+	densityOut, err := c.Shell(ctx, "wm", "density")
+	if err != nil {
+		return DisplayMetrics{}, err
+	}
+	density, err := strconv.Atoi(wmValue(densityOut))
+	if err != nil {
+		return DisplayMetrics{}, fmt.Errorf("unexpected wm density output: %q", strings.TrimSpace(densityOut))
+	}
+
+	c.display = DisplayMetrics{Width: int32(width), Height: int32(height), Density: int32(density)}
+	return c.display, nil
+}
+
+// wmValue picks the value from `wm` output such as "Physical size: 1080x2400",
+// letting an "Override ..." line win over the physical one.
+func wmValue(out string) string {
+	value := ""
+	for _, line := range strings.Split(out, "\n") {
+		label, v, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		if value == "" || strings.HasPrefix(strings.TrimSpace(label), "Override") {
+			value = strings.TrimSpace(v)
+		}
+	}
+	return value
+}
+
+// Shell runs a command through `adb shell` and returns its combined output.
+func (c *ADBClient) Shell(ctx context.Context, args ...string) (string, error) {
+	return c.run(ctx, append([]string{"shell"}, args...)...)
+}
+
+func (c *ADBClient) launcherActivity(ctx context.Context, packageName string) (string, error) {
+	out, err := c.Shell(ctx, "cmd", "package", "resolve-activity", "--brief", "-c", "android.intent.category.LAUNCHER", shellQuote(packageName))
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	last := strings.TrimSpace(lines[len(lines)-1])
+	if !strings.Contains(last, "/") {
+		return "", fmt.Errorf("no launcher activity for %s", packageName)
+	}
+	return last, nil
+}
+
+func (c *ADBClient) run(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, c.adbPath, append([]string{"-s", c.serial}, args...)...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return out.String(), fmt.Errorf("adb %s: %w: %s", args[0], err, strings.TrimSpace(out.String()))
+	}
+	return out.String(), nil
+}
+
+// shellQuote wraps s in single quotes for the device shell; adb joins shell
+// arguments with spaces, so URLs containing & or ? would otherwise be split and
+// a package name containing ; would run a second command.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func formatCoord(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	room := b.max - b.buf.Len()
+	if room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *cappedBuffer) String() string {
+	return b.buf.String()
+}
+
+func expectSuccess(op, out string) error {
+	if strings.Contains(out, "Success") {
+		return nil
+	}
+	return fmt.Errorf("%s failed: %s", op, strings.TrimSpace(out))
+}
@@ -0,0 +1,134 @@
+package android
+
+import (
+	"strings"
+
+	"github.com/fast-mobile-mcp/shared/snapshot"
+)
+
+// Alert is a system or app dialog found in a hierarchy dump.
+type Alert struct {
+	Text    string
+	Buttons []snapshot.Node
+	accept  int
+	dismiss int
+}
+
+// dialogPackages own the system dialogs agents run into: runtime permission
+// prompts, ANR/crash dialogs and the package installer.
+var dialogPackages = map[string]bool{
+	"android":                                 true,
+	"com.android.permissioncontroller":        true,
+	"com.google.android.permissioncontroller": true,
+	"com.android.packageinstaller":            true,
+	"com.google.android.packageinstaller":     true,
+	"com.android.systemui":                    true,
+}
+
+var alertMessageIDs = []string{
+	"android:id/message",
+	"android:id/alertTitle",
+	":id/permission_message",
+}
+
+// Button resource ids are matched by suffix so both the AOSP and the Google
+// permission controller packages are covered.
+var acceptButtonIDs = []string{
+	"android:id/button1",
+	":id/permission_allow_button",
+	":id/permission_allow_foreground_only_button",
+	":id/permission_allow_one_time_button",
+}
+
+var dismissButtonIDs = []string{
+	"android:id/button2",
+	":id/permission_deny_button",
+	":id/permission_deny_and_dont_ask_again_button",
+}
+
+// DetectAlert looks for a dialog in nodes. Standard AlertDialog ids are honored
+// in any package so app dialogs are handled the same way as system ones.
+func DetectAlert(nodes []snapshot.Node) (Alert, bool) {
+	alert := Alert{accept: -1, dismiss: -1}
+	texts := make([]string, 0, 2)
+	found := false
+
+	for _, n := range nodes {
+		if !n.Visible {
+			continue
+		}
+		switch {
+		case matchesID(n.ResourceID, alertMessageIDs):
+			found = true
+			if n.Text != "" {
+				texts = append(texts, n.Text)
+			}
+		case matchesID(n.ResourceID, acceptButtonIDs):
+			found = true
+			if alert.accept < 0 {
+				alert.accept = len(alert.Buttons)
+			}
+			alert.Buttons = append(alert.Buttons, n)
+		case matchesID(n.ResourceID, dismissButtonIDs):
+			found = true
+			if alert.dismiss < 0 {
+				alert.dismiss = len(alert.Buttons)
+			}
+			alert.Buttons = append(alert.Buttons, n)
+		case n.ResourceID == "android:id/button3",
+			dialogPackages[n.PackageName] && n.Clickable && strings.HasSuffix(n.ClassName, "Button"):
+			alert.Buttons = append(alert.Buttons, n)
+		}
+	}
+	if !found {
+		return Alert{}, false
+	}
+	alert.Text = strings.Join(texts, "\n")
+	return alert, true
+}
+
+// Button picks the button to tap: the one labelled label when given, otherwise
+// the accept or dismiss button.
+func (a Alert) Button(label string, accept bool) (snapshot.Node, bool) {
+	if label != "" {
+		for _, b := range a.Buttons {
+			if strings.EqualFold(strings.TrimSpace(b.Text), label) || strings.EqualFold(b.ContentDesc, label) {
+				return b, true
+			}
+		}
+		return snapshot.Node{}, false
+	}
+	idx := a.dismiss
+	if accept {
+		idx = a.accept
+	}
+	if idx < 0 {
+		return snapshot.Node{}, false
+	}
+	return a.Buttons[idx], true
+}
+
+// Labels returns the visible button labels in hierarchy order.
+func (a Alert) Labels() []string {
+	out := make([]string, 0, len(a.Buttons))
+	for _, b := range a.Buttons {
+		label := b.Text
+		if label == "" {
+			label = b.ContentDesc
+		}
+		out = append(out, label)
+	}
+	return out
+}
+
+func matchesID(resourceID string, ids []string) bool {
+	if resourceID == "" {
+		return false
+	}
+	for _, id := range ids {
+		if resourceID == id || (strings.HasPrefix(id, ":") && strings.HasSuffix(resourceID, id)) {
+			return true
+		}
+	}
+	return false
+}
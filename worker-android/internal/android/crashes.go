@@ -0,0 +1,162 @@
+package android
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxPendingCrashes = 32
+	maxCrashLines     = 20
+	logcatRetryDelay  = 2 * time.Second
+)
+
+// Crash is an app crash or ANR seen in the device log. Kind is "crash" or "anr".
+// App is the package that owns Process, which may be a ":remote" style
+// subprocess of it.
+type Crash struct {
+	Time    time.Time
+	Kind    string
+	Process string
+	App     string
+	Excerpt string
+}
+
+// CrashWatcher follows the crash and system log buffers for FATAL EXCEPTION and
+// ANR reports and keeps them until they are collected with Take or Pending.
+type CrashWatcher struct {
+	adb    *ADBClient
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu      sync.Mutex
+	pending []*crashReport
+	open    *crashReport
+}
+
+type crashReport struct {
+	crash Crash
+	tag   string
+	pid   int32
+	lines []string
+}
+
+func NewCrashWatcher(adb *ADBClient) *CrashWatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &CrashWatcher{adb: adb, cancel: cancel, done: make(chan struct{})}
+	go w.run(ctx)
+	return w
+}
+
+// Pending returns every crash not yet collected, oldest first.
+func (w *CrashWatcher) Pending() []Crash {
+	return w.Take(time.Time{}, time.Time{}, "")
+}
+
+// Take removes and returns the crashes logged between since and until whose
+// process belongs to app, oldest first. A zero bound or an empty app matches
+// anything. Crashes that do not match stay pending.
+func (w *CrashWatcher) Take(since, until time.Time, app string) []Crash {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var out []Crash
+	kept := w.pending[:0]
+	for _, report := range w.pending {
+		crash := report.crash
+		if !crashMatches(crash, since, until, app) {
+			kept = append(kept, report)
+			continue
+		}
+		crash.Excerpt = strings.Join(report.lines, "\n")
+		out = append(out, crash)
+		if report == w.open {
+			w.open = nil
+		}
+	}
+	clear(w.pending[len(kept):])
+	w.pending = kept
+	return out
+}
+
+func crashMatches(crash Crash, since, until time.Time, app string) bool {
+	if !since.IsZero() && crash.Time.Before(since) {
+		return false
+	}
+	if !until.IsZero() && crash.Time.After(until) {
+		return false
+	}
+	return app == "" || crash.App == app
+}
+
+func (w *CrashWatcher) Close() {
+	w.cancel()
+	<-w.done
+}
+
+func (w *CrashWatcher) run(ctx context.Context) {
+	defer close(w.done)
+	filter := LogcatFilter{
+		Buffers:  []string{"crash", "system"},
+		Tags:     []string{"AndroidRuntime", "ActivityManager"},
+		MinLevel: 'E',
+	}
+	for ctx.Err() == nil {
+		entries := make(chan LogEntry, 64)
+		go func() {
+			for entry := range entries {
+				w.observe(entry)
+			}
+		}()
+		// Logcat exits when the device drops off; retry and follow from then on.
+		_ = w.adb.Logcat(ctx, filter, entries)
+		select {
+		case <-ctx.Done():
+		case <-time.After(logcatRetryDelay):
+		}
+	}
+}
+
+func (w *CrashWatcher) observe(entry LogEntry) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	switch {
+	case entry.Tag == "AndroidRuntime" && strings.HasPrefix(entry.Message, "FATAL EXCEPTION"):
+		w.begin(&crashReport{crash: Crash{Time: entry.Time, Kind: "crash"}, tag: entry.Tag, pid: entry.PID})
+	case entry.Tag == "ActivityManager" && strings.HasPrefix(entry.Message, "ANR in "):
+		process := strings.Fields(strings.TrimPrefix(entry.Message, "ANR in "))
+		report := &crashReport{crash: Crash{Time: entry.Time, Kind: "anr"}, tag: entry.Tag, pid: entry.PID}
+		if len(process) > 0 {
+			report.crash.Process = process[0]
+			report.crash.App = processApp(process[0])
+		}
+		w.begin(report)
+	case w.open != nil && entry.Tag == w.open.tag && entry.PID == w.open.pid:
+		// "Process: com.example, PID: 1234" follows the FATAL EXCEPTION header.
+		if rest, ok := strings.CutPrefix(entry.Message, "Process: "); ok && w.open.crash.Process == "" {
+			w.open.crash.Process, _, _ = strings.Cut(rest, ",")
+			w.open.crash.App = processApp(w.open.crash.Process)
+		}
+	default:
+		return
+	}
+	if w.open != nil && len(w.open.lines) < maxCrashLines {
+		w.open.lines = append(w.open.lines, entry.Message)
+	}
+}
+
+func (w *CrashWatcher) begin(report *crashReport) {
+	if len(w.pending) == maxPendingCrashes {
+		w.pending = w.pending[1:]
+	}
+	w.pending = append(w.pending, report)
+	w.open = report
+}
+
+// processApp strips the ":name" suffix Android gives an app's extra processes.
+func processApp(process string) string {
+	app, _, _ := strings.Cut(process, ":")
+	return app
+}
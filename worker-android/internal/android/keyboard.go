@@ -0,0 +1,124 @@
+package android
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/fast-mobile-mcp/shared/snapshot"
+)
+
+// KeyboardState describes the soft keyboard as seen by the window manager.
+// BoundsKnown is false when the IME window's area could not be read, in which
+// case a shown keyboard has to be assumed to cover anything.
+type KeyboardState struct {
+	Shown       bool
+	IMEPackage  string
+	Bounds      snapshot.Bounds
+	BoundsKnown bool
+}
+
+const keycodeBack = "4"
+
+var (
+	inputShownPattern      = regexp.MustCompile(`mInputShown=(true|false)`)
+	curMethodPattern       = regexp.MustCompile(`mCurMethodId=([^\s/]+)`)
+	imeFramePattern        = regexp.MustCompile(`\b(?:mFrame|frame)=\[(-?\d+),(-?\d+)\]\[(-?\d+),(-?\d+)\]`)
+	contentInsetsPattern   = regexp.MustCompile(`mGivenContentInsets=\[(-?\d+),(-?\d+)\]\[(-?\d+),(-?\d+)\]`)
+	touchableRegionPattern = regexp.MustCompile(`touchable region=SkRegion\(((?:\(-?\d+,-?\d+,-?\d+,-?\d+\))*)\)`)
+	regionRectPattern      = regexp.MustCompile(`\((-?\d+),(-?\d+),(-?\d+),(-?\d+)\)`)
+)
+
+// KeyboardState combines `dumpsys input_method` for visibility and the active
+// IME with the InputMethod window for the area the keyboard covers.
+func (c *ADBClient) KeyboardState(ctx context.Context) (KeyboardState, error) {
+	imeOut, err := c.Shell(ctx, "dumpsys", "input_method")
+	if err != nil {
+		return KeyboardState{}, err
+	}
+	state := KeyboardState{}
+	if m := inputShownPattern.FindStringSubmatch(imeOut); len(m) == 2 {
+		state.Shown = m[1] == "true"
+	}
+	if m := curMethodPattern.FindStringSubmatch(imeOut); len(m) == 2 {
+		state.IMEPackage = m[1]
+	}
+	if !state.Shown {
+		return state, nil
+	}
+
+	windowOut, err := c.Shell(ctx, "dumpsys", "window", "InputMethod")
+	if err != nil {
+		return KeyboardState{}, err
+	}
+	state.Bounds, state.BoundsKnown = parseIMEBounds(windowOut)
+	return state, nil
+}
+
+// parseIMEBounds reads the area the keyboard covers from `dumpsys window
+// InputMethod`. Since Android 10 the IME window spans the whole screen, so its
+// frame alone says nothing; the touchable region, or failing that the frame
+// minus the content insets, is where the keys are.
+func parseIMEBounds(out string) (snapshot.Bounds, bool) {
+	if m := touchableRegionPattern.FindStringSubmatch(out); len(m) == 2 {
+		var union snapshot.Bounds
+		for _, r := range regionRectPattern.FindAllStringSubmatch(m[1], -1) {
+			union = unionBounds(union, boundsFromMatch(r))
+		}
+		if !emptyBounds(union) {
+			return union, true
+		}
+	}
+	m := imeFramePattern.FindStringSubmatch(out)
+	if len(m) != 5 {
+		return snapshot.Bounds{}, false
+	}
+	frame := boundsFromMatch(m)
+	if in := contentInsetsPattern.FindStringSubmatch(out); len(in) == 5 {
+		insets := boundsFromMatch(in)
+		frame.Left += insets.Left
+		frame.Top += insets.Top
+		frame.Right -= insets.Right
+		frame.Bottom -= insets.Bottom
+	}
+	if emptyBounds(frame) {
+		return snapshot.Bounds{}, false
+	}
+	return frame, true
+}
+
+func boundsFromMatch(m []string) snapshot.Bounds {
+	return snapshot.Bounds{Left: atoi32(m[1]), Top: atoi32(m[2]), Right: atoi32(m[3]), Bottom: atoi32(m[4])}
+}
+
+func unionBounds(a, b snapshot.Bounds) snapshot.Bounds {
+	if emptyBounds(a) {
+		return b
+	}
+	if emptyBounds(b) {
+		return a
+	}
+	return snapshot.Bounds{
+		Left:   min(a.Left, b.Left),
+		Top:    min(a.Top, b.Top),
+		Right:  max(a.Right, b.Right),
+		Bottom: max(a.Bottom, b.Bottom),
+	}
+}
+
+func emptyBounds(b snapshot.Bounds) bool {
+	return b.Right <= b.Left || b.Bottom <= b.Top
+}
+
+// HideKeyboard presses back, which closes the IME without navigating while the
+// keyboard is up. Callers should check KeyboardState first.
+func (c *ADBClient) HideKeyboard(ctx context.Context) error {
+	_, err := c.Shell(ctx, "input", "keyevent", keycodeBack)
+	return err
+}
+
+func atoi32(s string) int32 {
+	v, _ := strconv.Atoi(strings.TrimSpace(s))
+	return int32(v)
+}
@@ -0,0 +1,60 @@
+package android
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fast-mobile-mcp/shared/snapshot"
+)
+
+func TestParseIMEBounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		sample    string
+		want      snapshot.Bounds
+		wantKnown bool
+	}{
+		{
+			// The window spans the screen; only the touchable region shows the keys.
+			name:      "full-screen window on api 33",
+			sample:    "window_input_method_api33.txt",
+			want:      snapshot.Bounds{Left: 0, Top: 1518, Right: 1080, Bottom: 2400},
+			wantKnown: true,
+		},
+		{
+			// An empty touchable region falls back to the frame minus its
+			// content insets.
+			name:      "keyboard-sized window on api 28",
+			sample:    "window_input_method_api28.txt",
+			want:      snapshot.Bounds{Left: 0, Top: 954, Right: 1080, Bottom: 1794},
+			wantKnown: true,
+		},
+		{
+			name:   "no frame",
+			sample: "window_input_method_unreadable.txt",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := os.ReadFile(filepath.Join("testdata", tt.sample))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, known := parseIMEBounds(string(out))
+			if known != tt.wantKnown || got != tt.want {
+				t.Errorf("parseIMEBounds = %+v, %v; want %+v, %v", got, known, tt.want, tt.wantKnown)
+			}
+		})
+	}
+}
+
+func TestParseIMEBoundsRegionUnion(t *testing.T) {
+	out := "touchable region=SkRegion((0,1500,540,1600)(0,1600,1080,2400))\n" +
+		"Frames: parent=[0,0][1080,2400] display=[0,0][1080,2400] frame=[0,0][1080,2400]\n"
+	got, known := parseIMEBounds(out)
+	want := snapshot.Bounds{Left: 0, Top: 1500, Right: 1080, Bottom: 2400}
+	if !known || got != want {
+		t.Errorf("parseIMEBounds = %+v, %v; want %+v, true", got, known, want)
+	}
+}
@@ -0,0 +1,129 @@
+package android
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogEntry is one parsed logcat line. Level is the logcat priority letter
+// (V, D, I, W, E, F or A).
+type LogEntry struct {
+	Time    time.Time
+	Level   byte
+	Tag     string
+	PID     int32
+	TID     int32
+	Message string
+}
+
+// LogcatFilter narrows the logcat stream. MinLevel is a priority letter, PID
+// restricts output to a single process and Buffers overrides logcat's default
+// buffer set.
+type LogcatFilter struct {
+	Buffers  []string
+	Tags     []string
+	MinLevel byte
+	PID      string
+	Since    time.Time
+}
+
+// `-v threadtime -v epoch` lines: "1700000000.123  1234  1250 I Tag: message".
+var logcatLinePattern = regexp.MustCompile(`^\s*(\d+)\.(\d+)\s+(\d+)\s+(\d+)\s+([VDIWEFA])\s+(.*?)\s*: (.*)$`)
+
+// Logcat follows the device log and sends parsed entries until ctx ends or adb
+// exits. entries is closed when Logcat returns.
+func (c *ADBClient) Logcat(ctx context.Context, filter LogcatFilter, entries chan<- LogEntry) error {
+	defer close(entries)
+
+	since := filter.Since
+	if since.IsZero() {
+		since = time.Now()
+	}
+	args := []string{"-s", c.serial, "logcat", "-v", "threadtime", "-v", "epoch",
+		"-T", fmt.Sprintf("%d.%03d", since.Unix(), since.Nanosecond()/int(time.Millisecond))}
+	for _, buffer := range filter.Buffers {
+		args = append(args, "-b", buffer)
+	}
+	if filter.PID != "" {
+		args = append(args, "--pid="+filter.PID)
+	}
+	level := "V"
+	if filter.MinLevel != 0 {
+		level = string(filter.MinLevel)
+	}
+	if len(filter.Tags) > 0 {
+		for _, tag := range filter.Tags {
+			args = append(args, tag+":"+level)
+		}
+		args = append(args, "*:S")
+	} else {
+		args = append(args, "*:"+level)
+	}
+
+	cmd := exec.CommandContext(ctx, c.adbPath, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		entry, ok := parseLogcatLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		select {
+		case entries <- entry:
+		case <-ctx.Done():
+			_ = cmd.Wait()
+			return ctx.Err()
+		}
+	}
+	if err := cmd.Wait(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("logcat exited: %w", err)
+	}
+	return ctx.Err()
+}
+
+// PidOf returns the pid of a running package, or an error when it is not running.
+func (c *ADBClient) PidOf(ctx context.Context, packageName string) (string, error) {
+	out, err := c.Shell(ctx, "pidof", "-s", packageName)
+	pid := strings.TrimSpace(out)
+	if err != nil || pid == "" {
+		return "", fmt.Errorf("%s is not running", packageName)
+	}
+	return pid, nil
+}
+
+func parseLogcatLine(line string) (LogEntry, bool) {
+	m := logcatLinePattern.FindStringSubmatch(line)
+	if len(m) != 8 {
+		return LogEntry{}, false
+	}
+	sec, _ := strconv.ParseInt(m[1], 10, 64)
+	frac := m[2]
+	for len(frac) < 3 {
+		frac += "0"
+	}
+	ms, _ := strconv.ParseInt(frac[:3], 10, 64)
+	pid, _ := strconv.Atoi(m[3])
+	tid, _ := strconv.Atoi(m[4])
+	return LogEntry{
+		Time:    time.UnixMilli(sec*1000 + ms).UTC(),
+		Level:   m[5][0],
+		Tag:     m[6],
+		PID:     int32(pid),
+		TID:     int32(tid),
+		Message: m[7],
+	}, true
+}
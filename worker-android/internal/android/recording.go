@@ -0,0 +1,203 @@
+package android
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// screenrecord refuses time limits above three minutes, so longer recordings
+// are captured as consecutive segments.
+const screenrecordSegmentLimit = 180 * time.Second
+
+// ScreenRecording is a screenrecord session that rolls over to a new segment
+// file on the device each time the current one hits the time limit.
+type ScreenRecording struct {
+	adb      *ADBClient
+	id       string
+	bitRate  int
+	deadline time.Time
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopping chan struct{}
+	done     chan struct{}
+
+	mu       sync.Mutex
+	pid      string
+	stopped  bool
+	segments []string
+	err      error
+}
+
+// StartScreenRecord begins recording to /sdcard. bitRateMbps of zero keeps the
+// screenrecord default; maxDuration bounds the total across segments.
+func (c *ADBClient) StartScreenRecord(id string, bitRateMbps int, maxDuration time.Duration) *ScreenRecording {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &ScreenRecording{
+		adb:      c,
+		ctx:      ctx,
+		cancel:   cancel,
+		id:       id,
+		bitRate:  bitRateMbps * 1_000_000,
+		deadline: time.Now().Add(maxDuration),
+		stopping: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+func (r *ScreenRecording) run() {
+	defer close(r.done)
+	for index := 0; ; index++ {
+		remaining := time.Until(r.deadline)
+		if remaining <= 0 {
+			return
+		}
+		limit := screenrecordSegmentLimit
+		if remaining < limit {
+			limit = remaining.Round(time.Second)
+			if limit < time.Second {
+				limit = time.Second
+			}
+		}
+
+		remote := fmt.Sprintf("/sdcard/fast-mobile-mcp-%s-%03d.mp4", r.id, index)
+		// The shell prints its PID and then becomes screenrecord, so Stop can
+		// signal this recording without touching any other screenrecord.
+		command := []string{"echo $$; exec screenrecord", "--time-limit", strconv.Itoa(int(limit.Seconds()))}
+		if r.bitRate > 0 {
+			command = append(command, "--bit-rate", strconv.Itoa(r.bitRate))
+		}
+		command = append(command, shellQuote(remote))
+
+		r.mu.Lock()
+		if r.stopped {
+			r.mu.Unlock()
+			return
+		}
+		r.segments = append(r.segments, remote)
+		r.mu.Unlock()
+
+		err := r.segment(strings.Join(command, " "))
+		select {
+		case <-r.stopping:
+			return
+		default:
+		}
+		if err != nil {
+			r.mu.Lock()
+			r.err = fmt.Errorf("screenrecord segment %d: %w", index, err)
+			r.mu.Unlock()
+			return
+		}
+	}
+}
+
+// segment runs one screenrecord and waits for it to exit.
+func (r *ScreenRecording) segment(command string) error {
+	cmd := exec.CommandContext(r.ctx, r.adb.adbPath, "-s", r.adb.serial, "shell", command)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(stdout)
+	if line, err := reader.ReadString('\n'); err == nil {
+		r.setPID(strings.TrimSpace(line))
+	}
+	_, _ = io.Copy(io.Discard, reader)
+	err = cmd.Wait()
+	// The PID may be reused once the shell exits.
+	r.mu.Lock()
+	r.pid = ""
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// setPID records the running segment's PID, interrupting it straight away if
+// Stop already ran while the segment was starting.
+func (r *ScreenRecording) setPID(pid string) {
+	r.mu.Lock()
+	r.pid = pid
+	stopped := r.stopped
+	r.mu.Unlock()
+	if stopped {
+		r.interrupt(r.ctx, pid)
+	}
+}
+
+// interrupt sends SIGINT, which makes screenrecord write the mp4 trailer; a
+// killed process leaves an unplayable file behind.
+func (r *ScreenRecording) interrupt(ctx context.Context, pid string) {
+	if _, err := strconv.Atoi(pid); err != nil {
+		return
+	}
+	_, _ = r.adb.Shell(ctx, "kill", "-INT", pid)
+}
+
+// Stop interrupts screenrecord so it finalizes the current segment, then pulls
+// every segment into dir and removes them from the device. It returns the local
+// paths in recording order.
+func (r *ScreenRecording) Stop(ctx context.Context, dir string) ([]string, error) {
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return nil, errors.New("recording already stopped")
+	}
+	r.stopped = true
+	close(r.stopping)
+	pid := r.pid
+	r.mu.Unlock()
+
+	r.interrupt(ctx, pid)
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		r.cancel()
+		return nil, ctx.Err()
+	}
+	r.cancel()
+
+	r.mu.Lock()
+	segments := append([]string(nil), r.segments...)
+	recordErr := r.err
+	r.mu.Unlock()
+
+	local := make([]string, 0, len(segments))
+	for _, remote := range segments {
+		path := filepath.Join(dir, filepath.Base(remote))
+		if _, err := r.adb.run(ctx, "pull", remote, path); err != nil {
+			recordErr = errors.Join(recordErr, err)
+			continue
+		}
+		local = append(local, path)
+	}
+	_, _ = r.adb.Shell(ctx, append([]string{"rm", "-f"}, segments...)...)
+
+	// Segments that did pull are still worth returning after a failed rollover.
+	if len(local) == 0 {
+		if recordErr == nil {
+			recordErr = errors.New("no segments recorded")
+		}
+		return nil, recordErr
+	}
+	return local, nil
+}
@@ -163,6 +163,49 @@ func (c *UIA2Client) Screenshot(ctx context.Context) ([]byte, int32, int32, erro
 	return data, int32(cfg.Width), int32(cfg.Height), nil
 }
 
+func (c *UIA2Client) GetClipboard(ctx context.Context) (string, error) {
+	payload := map[string]any{}
+	if err := c.getJSON(ctx, "/clipboard", &payload); err != nil {
+		return "", err
+	}
+	return stringValue(payload, "text"), nil
+}
+
+func (c *UIA2Client) SetClipboard(ctx context.Context, text string) error {
+	return c.postJSON(ctx, "/clipboard", map[string]any{"text": text})
+}
+
+// Orientation returns the uiautomator orientation name: natural, left,
+// upsidedown or right.
+func (c *UIA2Client) Orientation(ctx context.Context) (string, error) {
+	payload := map[string]any{}
+	if err := c.getJSON(ctx, "/orientation", &payload); err != nil {
+		return "", err
+	}
+	return stringValue(payload, "orientation"), nil
+}
+
+func (c *UIA2Client) SetOrientation(ctx context.Context, orientation string) error {
+	return c.postJSON(ctx, "/orientation", map[string]any{"orientation": orientation})
+}
+
+func (c *UIA2Client) getJSON(ctx context.Context, route string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+route, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		bodyRaw, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("uia2 request failed status=%d body=%s", resp.StatusCode, string(bodyRaw))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
 func (c *UIA2Client) postJSON(ctx context.Context, route string, body any) error {
 	raw, err := json.Marshal(body)
 	if err != nil {
@@ -204,6 +247,7 @@ func walkHierarchy(node hierarchyNode, parentRef string, index int32, counter *i
 		Visible:     attrBool(node.Attrs, "visible-to-user"),
 		Selected:    attrBool(node.Attrs, "selected"),
 		Checked:     attrBool(node.Attrs, "checked"),
+		Focused:     attrBool(node.Attrs, "focused"),
 		Bounds:      parseBounds(attrValue(node.Attrs, "bounds")),
 	}
 	*out = append(*out, item)
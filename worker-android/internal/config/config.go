@@ -2,7 +2,9 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -12,15 +14,32 @@ type Config struct {
 	SnapshotTTL           time.Duration
 	SnapshotCleanup       time.Duration
 	MaxSnapshotsPerDevice int
+	ExecutorMaxQueueDepth int
 	ActionTimeout         time.Duration
+	AppTimeout            time.Duration
+	InstallTimeout        time.Duration
 	StreamChunkBytes      int
 	StreamMaxFPS          int
+	RecordingDir          string
+	RecordingMaxDuration  time.Duration
 	ADBPath               string
 	UIA2BasePort          int
 	UIA2DevicePort        int
+	ShellAllowlist        []string
+	ShellTimeout          time.Duration
+	ShellMaxOutputBytes   int
 	LogLevel              string
 }
 
+// defaultShellAllowlist covers the diagnostics and setup commands agents need
+// most. Patterns are regular expressions matched against the whole command line.
+var defaultShellAllowlist = []string{
+	`dumpsys( .*)?`,
+	`settings (get|put|list) .*`,
+	`am broadcast .*`,
+	`getprop( .*)?`,
+}
+
 func Load() Config {
 	return Config{
 		ListenAddr:            getEnv("GRPC_LISTEN_ADDR", ":50051"),
@@ -28,12 +47,20 @@ func Load() Config {
 		SnapshotTTL:           getDuration("SNAPSHOT_TTL", 30*time.Second),
 		SnapshotCleanup:       getDuration("SNAPSHOT_CLEANUP_INTERVAL", 10*time.Second),
 		MaxSnapshotsPerDevice: getInt("MAX_SNAPSHOTS_PER_DEVICE", 8),
+		ExecutorMaxQueueDepth: getInt("EXECUTOR_MAX_QUEUE_DEPTH", 64),
 		ActionTimeout:         getDuration("ACTION_TIMEOUT", 2*time.Second),
+		AppTimeout:            getDuration("APP_TIMEOUT", 20*time.Second),
+		InstallTimeout:        getDuration("INSTALL_TIMEOUT", 3*time.Minute),
 		StreamChunkBytes:      getInt("STREAM_CHUNK_BYTES", 65536),
 		StreamMaxFPS:          getInt("STREAM_MAX_FPS", 15),
+		RecordingDir:          getEnv("RECORDING_DIR", filepath.Join(os.TempDir(), "fast-mobile-mcp", "recordings")),
+		RecordingMaxDuration:  getDuration("RECORDING_MAX_DURATION", 30*time.Minute),
 		ADBPath:               getEnv("ADB_PATH", "adb"),
 		UIA2BasePort:          getInt("UIA2_BASE_PORT", 7900),
 		UIA2DevicePort:        getInt("UIA2_DEVICE_PORT", 7912),
+		ShellAllowlist:        getList("SHELL_ALLOWLIST", defaultShellAllowlist),
+		ShellTimeout:          getDuration("SHELL_TIMEOUT", 10*time.Second),
+		ShellMaxOutputBytes:   getInt("SHELL_MAX_OUTPUT_BYTES", 256*1024),
 		LogLevel:              getEnv("LOG_LEVEL", "info"),
 	}
 }
@@ -54,6 +81,21 @@ func getDuration(key string, fallback time.Duration) time.Duration {
 	return fallback
 }
 
+// getList splits key on ";" so regex patterns may contain commas.
+func getList(key string, fallback []string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	out := make([]string, 0)
+	for _, item := range strings.Split(v, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
 func getInt(key string, fallback int) int {
 	if v := os.Getenv(key); v != "" {
 		if parsed, err := strconv.Atoi(v); err == nil {
@@ -6,14 +6,20 @@ import (
 	"os/exec"
 	"sync"
 
+	"github.com/fast-mobile-mcp/shared/executor"
 	"github.com/fast-mobile-mcp/worker-android/internal/android"
 	"github.com/fast-mobile-mcp/worker-android/internal/config"
 )
 
 type Runtime struct {
 	DeviceID string
-	Executor *Executor
+	Executor *executor.Executor
 	UIA2     *android.UIA2Client
+	ADB      *android.ADBClient
+	Crashes  *android.CrashWatcher
+
+	coordsMu sync.Mutex
+	coords   Coordinates
 }
 
 type Registry struct {
@@ -57,21 +63,34 @@ func (r *Registry) RuntimeForDevice(ctx context.Context, deviceID string) (*Runt
 		return nil, err
 	}
 
+	adb := android.NewADBClient(r.cfg.ADBPath, deviceID)
 	runtime := &Runtime{
 		DeviceID: deviceID,
-		Executor: NewExecutor(256),
+		Executor: executor.NewExecutor(r.cfg.ExecutorMaxQueueDepth),
 		UIA2:     client,
+		ADB:      adb,
+		Crashes:  android.NewCrashWatcher(adb),
 	}
 
 	r.runtimes[deviceID] = runtime
 	return runtime, nil
 }
 
+// Lookup returns the runtime for a device that already has one, without
+// connecting to it.
+func (r *Registry) Lookup(deviceID string) (*Runtime, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	runtime, ok := r.runtimes[deviceID]
+	return runtime, ok
+}
+
 func (r *Registry) Close() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	for _, runtime := range r.runtimes {
 		runtime.Executor.Close()
+		runtime.Crashes.Close()
 	}
 }
 
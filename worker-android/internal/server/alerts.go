@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+	"github.com/fast-mobile-mcp/worker-android/internal/android"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errNoAlert            = errors.New("no alert present")
+	errInvalidAlertButton = errors.New("alert button not available")
+)
+
+// GetAlert reports the dialog currently on screen. uiautomator has no alert
+// API, so dialogs are recognised from well-known resource ids in the hierarchy.
+func (s *MobileService) GetAlert(ctx context.Context, req *mobilev1.GetAlertRequest) (*mobilev1.GetAlertResponse, error) {
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	out, err := runtime.Executor.Submit(ctx, executor.LaneQuery, func(runCtx context.Context) (any, error) {
+		nodes, dumpErr := runtime.UIA2.DumpHierarchy(runCtx)
+		if dumpErr != nil {
+			return nil, dumpErr
+		}
+		alert, ok := android.DetectAlert(nodes)
+		if !ok {
+			return nil, nil
+		}
+		return &alert, nil
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &mobilev1.GetAlertResponse{
+		DeviceId:         req.DeviceId,
+		ObservedAtUnixMs: time.Now().UTC().UnixMilli(),
+	}
+	if alert, ok := out.(*android.Alert); ok {
+		resp.Present = true
+		resp.Text = alert.Text
+		resp.Buttons = alert.Labels()
+	}
+	return resp, nil
+}
+
+func (s *MobileService) AcceptAlert(ctx context.Context, req *mobilev1.AlertActionRequest) (*mobilev1.ActionResponse, error) {
+	return s.alertAction(ctx, req, true)
+}
+
+func (s *MobileService) DismissAlert(ctx context.Context, req *mobilev1.AlertActionRequest) (*mobilev1.ActionResponse, error) {
+	return s.alertAction(ctx, req, false)
+}
+
+func (s *MobileService) alertAction(ctx context.Context, req *mobilev1.AlertActionRequest, accept bool) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	out, err := runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		nodes, dumpErr := runtime.UIA2.DumpHierarchy(runCtx)
+		if dumpErr != nil {
+			return nil, dumpErr
+		}
+		alert, ok := android.DetectAlert(nodes)
+		if !ok {
+			return nil, errNoAlert
+		}
+		button, ok := alert.Button(req.ButtonLabel, accept)
+		if !ok {
+			return nil, fmt.Errorf("%w: button %q not found in %v", errInvalidAlertButton, req.ButtonLabel, alert.Labels())
+		}
+		p := center(button.Bounds)
+		return button.Text, runtime.UIA2.Tap(runCtx, p.x, p.y, 1)
+	})
+	switch {
+	case errors.Is(err, errNoAlert):
+		return actionFailed(req.DeviceId, start, "NO_ALERT", err), nil
+	case errors.Is(err, errInvalidAlertButton):
+		return actionFailed(req.DeviceId, start, "INVALID_TARGET", err), nil
+	case err != nil:
+		return actionFailed(req.DeviceId, start, "ALERT_ACTION_FAILED", err), nil
+	}
+	resp := actionOK(req.DeviceId, start)
+	resp.Metadata["button"] = out.(string)
+	return resp, nil
+}
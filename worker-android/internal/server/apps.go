@@ -0,0 +1,194 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+	"github.com/fast-mobile-mcp/worker-android/internal/android"
+	"github.com/fast-mobile-mcp/worker-android/internal/device"
+)
+
+var errMissingAppID = errors.New("app_id is required")
+
+// Package names and activity components are checked before they reach the
+// device shell, where anything else could smuggle in a second command.
+var (
+	packagePattern   = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+	componentPattern = regexp.MustCompile(`^[A-Za-z0-9_.]+(/[A-Za-z0-9_.$]+)?$`)
+)
+
+func validateAppID(appID string) error {
+	if appID == "" {
+		return errMissingAppID
+	}
+	if !packagePattern.MatchString(appID) {
+		return fmt.Errorf("app_id %q is not a package name", appID)
+	}
+	return nil
+}
+
+func validateActivity(activity string) error {
+	if activity != "" && !componentPattern.MatchString(activity) {
+		return fmt.Errorf("activity %q is not an activity or component name", activity)
+	}
+	return nil
+}
+
+func (s *MobileService) LaunchApp(ctx context.Context, req *mobilev1.LaunchAppRequest) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	if err := validateAppID(req.AppId); err != nil {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", err), nil
+	}
+	if err := validateActivity(req.Activity); err != nil {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", err), nil
+	}
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.timeoutContext(ctx, req.Options, s.cfg.AppTimeout)
+	defer cancel()
+
+	_, err = runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		return nil, runtime.ADB.LaunchApp(runCtx, req.AppId, req.Activity, req.StopExisting)
+	})
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "LAUNCH_FAILED", err), nil
+	}
+	return actionOK(req.DeviceId, start), nil
+}
+
+func (s *MobileService) TerminateApp(ctx context.Context, req *mobilev1.AppRequest) (*mobilev1.ActionResponse, error) {
+	return s.appAction(ctx, req, "TERMINATE_FAILED", func(runCtx context.Context, rt *device.Runtime) error {
+		return rt.ADB.TerminateApp(runCtx, req.AppId)
+	})
+}
+
+func (s *MobileService) UninstallApp(ctx context.Context, req *mobilev1.AppRequest) (*mobilev1.ActionResponse, error) {
+	return s.appAction(ctx, req, "UNINSTALL_FAILED", func(runCtx context.Context, rt *device.Runtime) error {
+		return rt.ADB.UninstallApp(runCtx, req.AppId)
+	})
+}
+
+func (s *MobileService) ClearAppData(ctx context.Context, req *mobilev1.AppRequest) (*mobilev1.ActionResponse, error) {
+	return s.appAction(ctx, req, "CLEAR_DATA_FAILED", func(runCtx context.Context, rt *device.Runtime) error {
+		return rt.ADB.ClearAppData(runCtx, req.AppId)
+	})
+}
+
+func (s *MobileService) InstallApp(ctx context.Context, req *mobilev1.InstallAppRequest) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	if req.AppPath == "" {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", errors.New("app_path is required")), nil
+	}
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.timeoutContext(ctx, req.Options, s.cfg.InstallTimeout)
+	defer cancel()
+
+	_, err = runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		return nil, runtime.ADB.InstallApp(runCtx, req.AppPath)
+	})
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "INSTALL_FAILED", err), nil
+	}
+	return actionOK(req.DeviceId, start), nil
+}
+
+// appAction runs a package-scoped lifecycle command on the device executor and
+// reports it as an ActionResponse.
+func (s *MobileService) appAction(ctx context.Context, req *mobilev1.AppRequest, failCode string, run func(context.Context, *device.Runtime) error) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	if err := validateAppID(req.AppId); err != nil {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", err), nil
+	}
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.timeoutContext(ctx, req.Options, s.cfg.AppTimeout)
+	defer cancel()
+
+	_, err = runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		return nil, run(runCtx, runtime)
+	})
+	if err != nil {
+		return actionFailed(req.DeviceId, start, failCode, err), nil
+	}
+	return actionOK(req.DeviceId, start), nil
+}
+
+const foregroundPollInterval = 200 * time.Millisecond
+
+func (s *MobileService) OpenURL(ctx context.Context, req *mobilev1.OpenURLRequest) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	if req.Url == "" {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", errors.New("url is required")), nil
+	}
+	if req.WaitForApp && req.AppId == "" {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", errors.New("wait_for_app requires app_id")), nil
+	}
+	if req.AppId != "" {
+		if err := validateAppID(req.AppId); err != nil {
+			return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", err), nil
+		}
+	}
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.timeoutContext(ctx, req.Options, s.cfg.AppTimeout)
+	defer cancel()
+
+	_, err = runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		return nil, runtime.ADB.OpenURL(runCtx, req.Url, req.AppId)
+	})
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "OPEN_URL_FAILED", err), nil
+	}
+	if !req.WaitForApp {
+		return actionOK(req.DeviceId, start), nil
+	}
+
+	app, err := waitForForeground(ctx, runtime, req.AppId)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return actionFailed(req.DeviceId, start, "APP_NOT_FOREGROUND_TIMEOUT", err), nil
+	}
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "OPEN_URL_FAILED", err), nil
+	}
+	resp := actionOK(req.DeviceId, start)
+	resp.Metadata["foreground_app"] = app
+	return resp, nil
+}
+
+// waitForForeground polls the active app until appID is in front or ctx ends.
+// Each poll is its own short query job, so waiting for a slow app does not hold
+// the device against other callers.
+func waitForForeground(ctx context.Context, runtime *device.Runtime, appID string) (string, error) {
+	for {
+		out, err := runtime.Executor.Submit(ctx, executor.LaneQuery, func(runCtx context.Context) (any, error) {
+			return runtime.UIA2.GetActiveApp(runCtx)
+		})
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if err == nil {
+			if app := out.(android.ActiveApp); app.BundleID == appID {
+				return app.BundleID, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(foregroundPollInterval):
+		}
+	}
+}
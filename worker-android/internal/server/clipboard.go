@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *MobileService) GetClipboard(ctx context.Context, req *mobilev1.GetClipboardRequest) (*mobilev1.GetClipboardResponse, error) {
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	out, err := runtime.Executor.Submit(ctx, executor.LaneQuery, func(runCtx context.Context) (any, error) {
+		return runtime.UIA2.GetClipboard(runCtx)
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &mobilev1.GetClipboardResponse{
+		DeviceId:         req.DeviceId,
+		Text:             out.(string),
+		ObservedAtUnixMs: time.Now().UTC().UnixMilli(),
+	}, nil
+}
+
+func (s *MobileService) SetClipboard(ctx context.Context, req *mobilev1.SetClipboardRequest) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	_, err = runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		return nil, runtime.UIA2.SetClipboard(runCtx, req.Text)
+	})
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "SET_CLIPBOARD_FAILED", err), nil
+	}
+	return actionOK(req.DeviceId, start), nil
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fast-mobile-mcp/worker-android/internal/android"
+)
+
+// fakeCrashes keeps crashes in memory and applies the watcher's Take rules.
+type fakeCrashes struct {
+	pending []android.Crash
+}
+
+func (f *fakeCrashes) Take(since, until time.Time, app string) []android.Crash {
+	var out, kept []android.Crash
+	for _, crash := range f.pending {
+		if crash.Time.Before(since) || crash.Time.After(until) || (app != "" && crash.App != app) {
+			kept = append(kept, crash)
+			continue
+		}
+		out = append(out, crash)
+	}
+	f.pending = kept
+	return out
+}
+
+func TestClaimCrashes(t *testing.T) {
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	at := func(ms int) time.Time { return base.Add(time.Duration(ms) * time.Millisecond) }
+	previous := &actionWindow{started: at(0), ended: at(100), app: "com.example.app"}
+	action := actionWindow{started: at(3000), ended: at(3100), app: "com.example.app"}
+
+	tests := []struct {
+		name     string
+		crash    android.Crash
+		action   actionWindow
+		previous *actionWindow
+		claimed  bool
+		late     bool
+	}{
+		{"crash while the action ran", android.Crash{Time: at(3050), App: "com.example.app"}, action, previous, true, false},
+		{"crash just after the action, within grace", android.Crash{Time: at(3400), App: "com.example.app"}, action, previous, true, false},
+		{"crash just before the action, within grace", android.Crash{Time: at(2700), App: "com.example.app"}, action, nil, true, false},
+		{"crash after the previous action returned", android.Crash{Time: at(900), App: "com.example.app"}, action, previous, true, true},
+		{"crash after the previous action with no previous action", android.Crash{Time: at(900), App: "com.example.app"}, action, nil, false, false},
+		{"crash of another app", android.Crash{Time: at(3050), App: "com.other"}, action, previous, false, false},
+		{"crash of another app after the previous action", android.Crash{Time: at(900), App: "com.other"}, action, previous, false, false},
+		{"crash before the previous action", android.Crash{Time: at(-2000), App: "com.example.app"}, action, previous, false, false},
+		{"crash long after the previous action", android.Crash{Time: at(20000), App: "com.example.app"},
+			actionWindow{started: at(30000), ended: at(30100), app: "com.example.app"}, previous, false, false},
+		{"unknown target app claims any app", android.Crash{Time: at(3050), App: "com.other"},
+			actionWindow{started: at(3000), ended: at(3100)}, previous, true, false},
+		{"previous action's app is used for late crashes", android.Crash{Time: at(900), App: "com.example.app"},
+			actionWindow{started: at(3000), ended: at(3100), app: "com.other"}, previous, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watcher := &fakeCrashes{pending: []android.Crash{tt.crash}}
+			crashes, late := claimCrashes(watcher, tt.action, tt.previous)
+			if claimed := len(crashes) == 1; claimed != tt.claimed {
+				t.Fatalf("claimed = %v, want %v", claimed, tt.claimed)
+			}
+			if late != tt.late {
+				t.Errorf("late = %v, want %v", late, tt.late)
+			}
+			wantLeft := 1
+			if tt.claimed {
+				wantLeft = 0
+			}
+			if left := len(watcher.pending); left != wantLeft {
+				t.Errorf("%d crashes left pending, want %d; unclaimed crashes stay for GetCrashes", left, wantLeft)
+			}
+		})
+	}
+}
+
+func TestActionLogSwap(t *testing.T) {
+	log := newActionLog()
+	first := actionWindow{started: time.Unix(1, 0), app: "a"}
+	if previous := log.swap("device", first); previous != nil {
+		t.Fatalf("first action has previous %+v", previous)
+	}
+	if previous := log.swap("other", actionWindow{app: "b"}); previous != nil {
+		t.Fatalf("devices share history: %+v", previous)
+	}
+	if previous := log.swap("device", actionWindow{app: "c"}); previous == nil || *previous != first {
+		t.Fatalf("previous = %+v, want %+v", previous, first)
+	}
+}
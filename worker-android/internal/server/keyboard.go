@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+	"github.com/fast-mobile-mcp/shared/snapshot"
+	"github.com/fast-mobile-mcp/worker-android/internal/android"
+	"github.com/fast-mobile-mcp/worker-android/internal/device"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *MobileService) GetKeyboardState(ctx context.Context, req *mobilev1.GetKeyboardStateRequest) (*mobilev1.GetKeyboardStateResponse, error) {
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	out, err := runtime.Executor.Submit(ctx, executor.LaneQuery, func(runCtx context.Context) (any, error) {
+		return runtime.ADB.KeyboardState(runCtx)
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	state := out.(android.KeyboardState)
+	resp := &mobilev1.GetKeyboardStateResponse{
+		DeviceId:         req.DeviceId,
+		Shown:            state.Shown,
+		ImePackage:       state.IMEPackage,
+		ObservedAtUnixMs: time.Now().UTC().UnixMilli(),
+	}
+	if state.BoundsKnown {
+		resp.Height = state.Bounds.Bottom - state.Bounds.Top
+		resp.Bounds = convertBounds(state.Bounds)
+	}
+	return resp, nil
+}
+
+func (s *MobileService) HideKeyboard(ctx context.Context, req *mobilev1.HideKeyboardRequest) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	out, err := runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		state, stateErr := runtime.ADB.KeyboardState(runCtx)
+		if stateErr != nil {
+			return nil, stateErr
+		}
+		// Back would navigate away when no keyboard is up, so only press it
+		// while the IME is shown.
+		if !state.Shown {
+			return false, nil
+		}
+		return true, runtime.ADB.HideKeyboard(runCtx)
+	})
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "HIDE_KEYBOARD_FAILED", err), nil
+	}
+	resp := actionOK(req.DeviceId, start)
+	resp.Metadata["was_shown"] = strconv.FormatBool(out.(bool))
+	return resp, nil
+}
+
+// uncoverTarget hides the keyboard when it covers p and returns where the target
+// sits once the layout has settled; a resized window usually moves it. A shown
+// keyboard whose area could not be read is always hidden.
+func uncoverTarget(ctx context.Context, runtime *device.Runtime, p point, node *snapshot.Node) (point, bool, error) {
+	state, err := runtime.ADB.KeyboardState(ctx)
+	if err != nil {
+		return p, false, err
+	}
+	if !state.Shown || (state.BoundsKnown && !contains(state.Bounds, p)) {
+		return p, false, nil
+	}
+	if err := runtime.ADB.HideKeyboard(ctx); err != nil {
+		return p, false, err
+	}
+	if node == nil {
+		return p, true, nil
+	}
+	nodes, err := runtime.UIA2.DumpHierarchy(ctx)
+	if err != nil {
+		return p, true, err
+	}
+	if moved, ok := relocate(*node, nodes); ok {
+		return center(moved.Bounds), true, nil
+	}
+	return p, true, nil
+}
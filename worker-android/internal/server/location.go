@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+	"github.com/fast-mobile-mcp/worker-android/internal/device"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *MobileService) SetLocation(ctx context.Context, req *mobilev1.SetLocationRequest) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	if err := validateLocation(req.Location); err != nil {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", err), nil
+	}
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	if err := applyLocation(ctx, runtime, executor.LaneInteractive, req.Location); err != nil {
+		return actionFailed(req.DeviceId, start, "SET_LOCATION_FAILED", err), nil
+	}
+	return actionOK(req.DeviceId, start), nil
+}
+
+// PlayRoute replays waypoints at their offsets, scaled by speed_multiplier. Each
+// fix is its own background executor job so taps and dumps interleave with a
+// long route.
+func (s *MobileService) PlayRoute(req *mobilev1.PlayRouteRequest, stream mobilev1.MobileAutomationService_PlayRouteServer) error {
+	speed, err := validateRoute(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	runtime, err := s.registry.RuntimeForDevice(stream.Context(), req.DeviceId)
+	if err != nil {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	routeStart := time.Now()
+	for i, wp := range req.Waypoints {
+		due := routeStart.Add(time.Duration(float64(wp.OffsetMs)/speed) * time.Millisecond)
+		if wait := time.Until(due); wait > 0 {
+			select {
+			case <-stream.Context().Done():
+				return stream.Context().Err()
+			case <-time.After(wait):
+			}
+		}
+
+		fixCtx, cancel := s.actionContext(stream.Context(), req.Options)
+		err := applyLocation(fixCtx, runtime, executor.LaneBackground, wp.Location)
+		cancel()
+		if err != nil {
+			return status.Error(codes.Internal, fmt.Sprintf("waypoint %d: %v", i, err))
+		}
+
+		if err := stream.Send(&mobilev1.PlayRouteEvent{
+			Payload: &mobilev1.PlayRouteEvent_Progress{Progress: &mobilev1.RouteProgress{
+				WaypointIndex:   uint32(i),
+				Location:        wp.Location,
+				AppliedAtUnixMs: time.Now().UTC().UnixMilli(),
+			}},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return stream.Send(&mobilev1.PlayRouteEvent{
+		Payload: &mobilev1.PlayRouteEvent_End{End: &mobilev1.StreamEnd{Reason: "route_complete"}},
+	})
+}
+
+// validateRoute checks the waypoints and returns the speed multiplier to replay
+// them at; zero means real time.
+func validateRoute(req *mobilev1.PlayRouteRequest) (float64, error) {
+	if len(req.Waypoints) == 0 {
+		return 0, fmt.Errorf("waypoints are required")
+	}
+	var lastOffset int64
+	for i, wp := range req.Waypoints {
+		if err := validateLocation(wp.Location); err != nil {
+			return 0, fmt.Errorf("waypoint %d: %v", i, err)
+		}
+		if wp.OffsetMs < lastOffset {
+			return 0, fmt.Errorf("waypoint %d: offset_ms must be non-negative and not decrease", i)
+		}
+		lastOffset = wp.OffsetMs
+	}
+	speed := req.SpeedMultiplier
+	// NaN and +Inf pass a <= 0 check and would turn every offset into garbage.
+	if math.IsNaN(speed) || math.IsInf(speed, 0) || speed < 0 {
+		return 0, fmt.Errorf("speed_multiplier must be a finite, non-negative number")
+	}
+	if speed == 0 {
+		speed = 1
+	}
+	return speed, nil
+}
+
+func applyLocation(ctx context.Context, runtime *device.Runtime, lane executor.Lane, loc *mobilev1.Location) error {
+	_, err := runtime.Executor.Submit(ctx, lane, func(runCtx context.Context) (any, error) {
+		return nil, runtime.ADB.SetLocation(runCtx, loc.Latitude, loc.Longitude, loc.Altitude)
+	})
+	return err
+}
+
+func validateLocation(loc *mobilev1.Location) error {
+	if loc == nil {
+		return fmt.Errorf("location is required")
+	}
+	// NaN fails every comparison, so it would slip through the range checks.
+	for _, v := range []float64{loc.Latitude, loc.Longitude, loc.Altitude} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("location values must be finite")
+		}
+	}
+	if loc.Latitude < -90 || loc.Latitude > 90 {
+		return fmt.Errorf("latitude %v out of range", loc.Latitude)
+	}
+	if loc.Longitude < -180 || loc.Longitude > 180 {
+		return fmt.Errorf("longitude %v out of range", loc.Longitude)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"math"
+	"testing"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+)
+
+func TestValidateRoute(t *testing.T) {
+	waypoint := func(offsetMs int64) *mobilev1.RouteWaypoint {
+		return &mobilev1.RouteWaypoint{Location: &mobilev1.Location{Latitude: 52.5, Longitude: 13.4}, OffsetMs: offsetMs}
+	}
+	route := []*mobilev1.RouteWaypoint{waypoint(0), waypoint(1000), waypoint(1000), waypoint(2500)}
+
+	tests := []struct {
+		name      string
+		waypoints []*mobilev1.RouteWaypoint
+		speed     float64
+		wantSpeed float64
+		wantErr   bool
+	}{
+		{name: "default speed", waypoints: route, wantSpeed: 1},
+		{name: "explicit speed", waypoints: route, speed: 2.5, wantSpeed: 2.5},
+		{name: "no waypoints", wantErr: true},
+		{name: "decreasing offset", waypoints: []*mobilev1.RouteWaypoint{waypoint(0), waypoint(2000), waypoint(1500)}, wantErr: true},
+		{name: "negative offset", waypoints: []*mobilev1.RouteWaypoint{waypoint(-1)}, wantErr: true},
+		{name: "missing location", waypoints: []*mobilev1.RouteWaypoint{{OffsetMs: 0}}, wantErr: true},
+		{name: "nan speed", waypoints: route, speed: math.NaN(), wantErr: true},
+		{name: "infinite speed", waypoints: route, speed: math.Inf(1), wantErr: true},
+		{name: "negative speed", waypoints: route, speed: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			speed, err := validateRoute(&mobilev1.PlayRouteRequest{Waypoints: tt.waypoints, SpeedMultiplier: tt.speed})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("validateRoute returned speed %v, want an error", speed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if speed != tt.wantSpeed {
+				t.Errorf("speed = %v, want %v", speed, tt.wantSpeed)
+			}
+		})
+	}
+}
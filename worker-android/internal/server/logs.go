@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/worker-android/internal/android"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const logHeartbeatInterval = 5 * time.Second
+
+var logcatLevels = map[mobilev1.LogLevel]byte{
+	mobilev1.LogLevel_LOG_LEVEL_VERBOSE: 'V',
+	mobilev1.LogLevel_LOG_LEVEL_DEBUG:   'D',
+	mobilev1.LogLevel_LOG_LEVEL_INFO:    'I',
+	mobilev1.LogLevel_LOG_LEVEL_WARN:    'W',
+	mobilev1.LogLevel_LOG_LEVEL_ERROR:   'E',
+	mobilev1.LogLevel_LOG_LEVEL_FATAL:   'F',
+}
+
+// StreamLogs tails logcat for the device. It does not go through the executor:
+// the adb process lives for the whole stream and must not hold up UI actions.
+func (s *MobileService) StreamLogs(req *mobilev1.StreamLogsRequest, stream mobilev1.MobileAutomationService_StreamLogsServer) error {
+	if req.Predicate != "" {
+		return status.Error(codes.InvalidArgument, "predicate is only supported on iOS")
+	}
+	runtime, err := s.registry.RuntimeForDevice(stream.Context(), req.DeviceId)
+	if err != nil {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	filter := android.LogcatFilter{Tags: req.Tags, MinLevel: logcatLevels[req.MinLevel]}
+	if req.SinceUnixMs > 0 {
+		filter.Since = time.UnixMilli(req.SinceUnixMs)
+	}
+	if req.PackageName != "" {
+		pid, err := runtime.ADB.PidOf(ctx, req.PackageName)
+		if err != nil {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+		filter.PID = pid
+	}
+
+	entries := make(chan android.LogEntry, 64)
+	done := make(chan error, 1)
+	go func() { done <- runtime.ADB.Logcat(ctx, filter, entries) }()
+
+	heartbeat := time.NewTicker(logHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	sent := uint32(0)
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-heartbeat.C:
+			if err := stream.Send(&mobilev1.StreamLogsEvent{
+				Payload: &mobilev1.StreamLogsEvent_Heartbeat{Heartbeat: &mobilev1.StreamHeartbeat{UnixMs: time.Now().UTC().UnixMilli()}},
+			}); err != nil {
+				return err
+			}
+		case entry, ok := <-entries:
+			if !ok {
+				if err := <-done; err != nil && stream.Context().Err() == nil {
+					return status.Error(codes.Unavailable, err.Error())
+				}
+				return stream.Send(&mobilev1.StreamLogsEvent{
+					Payload: &mobilev1.StreamLogsEvent_End{End: &mobilev1.StreamEnd{Reason: "log_closed"}},
+				})
+			}
+			if err := stream.Send(&mobilev1.StreamLogsEvent{
+				Payload: &mobilev1.StreamLogsEvent_Entry{Entry: convertLogEntry(entry, req.PackageName)},
+			}); err != nil {
+				return err
+			}
+			sent++
+			if req.MaxEntries > 0 && sent >= req.MaxEntries {
+				return stream.Send(&mobilev1.StreamLogsEvent{
+					Payload: &mobilev1.StreamLogsEvent_End{End: &mobilev1.StreamEnd{Reason: "max_entries_reached"}},
+				})
+			}
+		}
+	}
+}
+
+func convertLogEntry(entry android.LogEntry, process string) *mobilev1.LogEntry {
+	level := mobilev1.LogLevel_LOG_LEVEL_FATAL
+	for l, letter := range logcatLevels {
+		if letter == entry.Level {
+			level = l
+			break
+		}
+	}
+	return &mobilev1.LogEntry{
+		TimestampUnixMs: entry.Time.UnixMilli(),
+		Level:           level,
+		Tag:             entry.Tag,
+		Pid:             entry.PID,
+		Tid:             int64(entry.TID),
+		Process:         process,
+		Message:         entry.Message,
+	}
+}
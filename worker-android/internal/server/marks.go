@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"image"
+	"strconv"
+	"strings"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+	"github.com/fast-mobile-mcp/shared/imaging"
+	"github.com/fast-mobile-mcp/shared/snapshot"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type markedCapture struct {
+	nodes []snapshot.Node
+	data  []byte
+}
+
+// AnnotatedScreenshot captures the hierarchy and a frame in one executor job,
+// stores the hierarchy as a snapshot and draws each clickable node's number on
+// the frame. Mark labels are the numeric part of the node's ref_id, so "tap 17"
+// maps straight to ref_id n-17 in the returned snapshot.
+func (s *MobileService) AnnotatedScreenshot(ctx context.Context, req *mobilev1.AnnotatedScreenshotRequest) (*mobilev1.AnnotatedScreenshotResponse, error) {
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	out, err := runtime.Executor.Submit(ctx, executor.LaneQuery, func(runCtx context.Context) (any, error) {
+		nodes, err := s.dumpHierarchy(runCtx, runtime)
+		if err != nil {
+			return nil, err
+		}
+		data, _, _, err := runtime.UIA2.Screenshot(runCtx)
+		if err != nil {
+			return nil, err
+		}
+		return markedCapture{nodes: nodes, data: data}, nil
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	capturedAt := time.Now().UTC()
+	capture := out.(markedCapture)
+	snap := s.store.Put(req.DeviceId, capture.nodes)
+
+	img, _, err := imaging.Decode(capture.data)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	fitted := imaging.Fit(img, int(req.MaxWidth), int(req.MaxHeight))
+	scale := float64(fitted.Bounds().Dx()) / float64(img.Bounds().Dx())
+	marks, drawn := setOfMarks(capture.nodes, scale)
+
+	format := imaging.PNG
+	if req.Format == mobilev1.ImageFormat_IMAGE_FORMAT_JPEG {
+		format = imaging.JPEG
+	}
+	annotated := imaging.DrawMarks(fitted, drawn)
+	data, err := imaging.Encode(annotated, format, int(req.JpegQuality))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &mobilev1.AnnotatedScreenshotResponse{
+		DeviceId:         req.DeviceId,
+		SnapshotId:       snap.ID,
+		ExpiresAtUnixMs:  snap.ExpiresAt.UnixMilli(),
+		Data:             data,
+		MimeType:         format.MimeType(),
+		Width:            uint32(annotated.Rect.Dx()),
+		Height:           uint32(annotated.Rect.Dy()),
+		Marks:            marks,
+		CapturedAtUnixMs: capturedAt.UnixMilli(),
+	}, nil
+}
+
+// setOfMarks picks the visible clickable nodes and maps their bounds onto the
+// image with scale, the ratio of image pixels to node coordinates.
+func setOfMarks(nodes []snapshot.Node, scale float64) ([]*mobilev1.ScreenMark, []imaging.Mark) {
+	px := func(v int32) int { return int(float64(v)*scale + 0.5) }
+	marks := make([]*mobilev1.ScreenMark, 0)
+	drawn := make([]imaging.Mark, 0)
+	for _, n := range nodes {
+		if !n.Clickable || !n.Visible || n.Bounds.Right <= n.Bounds.Left || n.Bounds.Bottom <= n.Bounds.Top {
+			continue
+		}
+		label, err := strconv.ParseUint(strings.TrimPrefix(n.RefID, "n-"), 10, 32)
+		if err != nil {
+			continue
+		}
+		marks = append(marks, &mobilev1.ScreenMark{
+			Label:       uint32(label),
+			RefId:       n.RefID,
+			Bounds:      convertBounds(n.Bounds),
+			Text:        n.Text,
+			ContentDesc: n.ContentDesc,
+		})
+		drawn = append(drawn, imaging.Mark{
+			Label: strconv.FormatUint(label, 10),
+			Rect:  image.Rect(px(n.Bounds.Left), px(n.Bounds.Top), px(n.Bounds.Right), px(n.Bounds.Bottom)),
+		})
+	}
+	return marks, drawn
+}
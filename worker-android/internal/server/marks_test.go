@@ -0,0 +1,41 @@
+package server
+
+import (
+	"image"
+	"testing"
+
+	"github.com/fast-mobile-mcp/shared/snapshot"
+)
+
+func TestSetOfMarks(t *testing.T) {
+	bounds := snapshot.Bounds{Left: 10, Top: 20, Right: 110, Bottom: 60}
+	node := func(refID string) snapshot.Node {
+		return snapshot.Node{RefID: refID, Bounds: bounds, Clickable: true, Visible: true}
+	}
+	hidden := node("n-4")
+	hidden.Visible = false
+	flat := node("n-5")
+	flat.Bounds.Bottom = flat.Bounds.Top
+
+	nodes := []snapshot.Node{node("n-17"), node("n-0"), node("x-3"), node("n-"), node("n-2a"), hidden, flat}
+	marks, drawn := setOfMarks(nodes, 0.5)
+
+	wantLabels := []uint32{17, 0}
+	if len(marks) != len(wantLabels) || len(drawn) != len(wantLabels) {
+		t.Fatalf("got %d marks and %d drawn, want %d", len(marks), len(drawn), len(wantLabels))
+	}
+	for i, want := range wantLabels {
+		if marks[i].Label != want {
+			t.Errorf("mark %d label = %d, want %d", i, marks[i].Label, want)
+		}
+		if wantRef := nodes[i].RefID; marks[i].RefId != wantRef {
+			t.Errorf("mark %d ref_id = %q, want %q", i, marks[i].RefId, wantRef)
+		}
+	}
+	if drawn[0].Label != "17" {
+		t.Errorf("drawn label = %q, want 17", drawn[0].Label)
+	}
+	if want := image.Rect(5, 10, 55, 30); drawn[0].Rect != want {
+		t.Errorf("drawn rect = %v, want %v scaled by 0.5", drawn[0].Rect, want)
+	}
+}
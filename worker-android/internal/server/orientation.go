@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// uiautomator names rotations relative to the natural (portrait) orientation.
+var orientationNames = map[mobilev1.Orientation]string{
+	mobilev1.Orientation_ORIENTATION_PORTRAIT:          "natural",
+	mobilev1.Orientation_ORIENTATION_LANDSCAPE:         "left",
+	mobilev1.Orientation_ORIENTATION_PORTRAIT_REVERSE:  "upsidedown",
+	mobilev1.Orientation_ORIENTATION_LANDSCAPE_REVERSE: "right",
+}
+
+func orientationFromBackend(raw string) mobilev1.Orientation {
+	for o, name := range orientationNames {
+		if name == raw {
+			return o
+		}
+	}
+	return mobilev1.Orientation_ORIENTATION_UNSPECIFIED
+}
+
+func (s *MobileService) GetOrientation(ctx context.Context, req *mobilev1.GetOrientationRequest) (*mobilev1.GetOrientationResponse, error) {
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	out, err := runtime.Executor.Submit(ctx, executor.LaneQuery, func(runCtx context.Context) (any, error) {
+		return runtime.UIA2.Orientation(runCtx)
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &mobilev1.GetOrientationResponse{
+		DeviceId:         req.DeviceId,
+		Orientation:      orientationFromBackend(out.(string)),
+		ObservedAtUnixMs: time.Now().UTC().UnixMilli(),
+	}, nil
+}
+
+func (s *MobileService) SetOrientation(ctx context.Context, req *mobilev1.SetOrientationRequest) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	name, ok := orientationNames[req.Orientation]
+	if !ok {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", errors.New("orientation is required")), nil
+	}
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	_, err = runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		return nil, runtime.UIA2.SetOrientation(runCtx, name)
+	})
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "SET_ORIENTATION_FAILED", err), nil
+	}
+	return actionOK(req.DeviceId, start), nil
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+	"github.com/fast-mobile-mcp/worker-android/internal/device"
+)
+
+// runtimePermissions maps cross-platform permissions to the Android runtime
+// permissions behind them. Several entries cover more than one manifest
+// permission because apps request different subsets across API levels.
+var runtimePermissions = map[mobilev1.Permission][]string{
+	mobilev1.Permission_PERMISSION_CAMERA:          {"android.permission.CAMERA"},
+	mobilev1.Permission_PERMISSION_MICROPHONE:      {"android.permission.RECORD_AUDIO"},
+	mobilev1.Permission_PERMISSION_LOCATION:        {"android.permission.ACCESS_FINE_LOCATION", "android.permission.ACCESS_COARSE_LOCATION"},
+	mobilev1.Permission_PERMISSION_LOCATION_ALWAYS: {"android.permission.ACCESS_FINE_LOCATION", "android.permission.ACCESS_COARSE_LOCATION", "android.permission.ACCESS_BACKGROUND_LOCATION"},
+	mobilev1.Permission_PERMISSION_CONTACTS:        {"android.permission.READ_CONTACTS", "android.permission.WRITE_CONTACTS"},
+	mobilev1.Permission_PERMISSION_CALENDAR:        {"android.permission.READ_CALENDAR", "android.permission.WRITE_CALENDAR"},
+	mobilev1.Permission_PERMISSION_PHOTOS:          {"android.permission.READ_MEDIA_IMAGES", "android.permission.READ_EXTERNAL_STORAGE"},
+	mobilev1.Permission_PERMISSION_NOTIFICATIONS:   {"android.permission.POST_NOTIFICATIONS"},
+}
+
+func (s *MobileService) GrantPermission(ctx context.Context, req *mobilev1.PermissionRequest) (*mobilev1.ActionResponse, error) {
+	return s.permissionAction(ctx, req, false, "GRANT_FAILED", func(runCtx context.Context, runtime *device.Runtime, perm string) error {
+		return runtime.ADB.GrantPermission(runCtx, req.AppId, perm)
+	})
+}
+
+func (s *MobileService) RevokePermission(ctx context.Context, req *mobilev1.PermissionRequest) (*mobilev1.ActionResponse, error) {
+	return s.permissionAction(ctx, req, false, "REVOKE_FAILED", func(runCtx context.Context, runtime *device.Runtime, perm string) error {
+		return runtime.ADB.RevokePermission(runCtx, req.AppId, perm)
+	})
+}
+
+// ResetPermissions revokes and clears the user flags so the next request shows
+// the system dialog again. An unspecified permission resets all known ones.
+func (s *MobileService) ResetPermissions(ctx context.Context, req *mobilev1.PermissionRequest) (*mobilev1.ActionResponse, error) {
+	return s.permissionAction(ctx, req, true, "RESET_FAILED", func(runCtx context.Context, runtime *device.Runtime, perm string) error {
+		if err := runtime.ADB.RevokePermission(runCtx, req.AppId, perm); err != nil {
+			return err
+		}
+		return runtime.ADB.ClearPermissionFlags(runCtx, req.AppId, perm)
+	})
+}
+
+func (s *MobileService) permissionAction(ctx context.Context, req *mobilev1.PermissionRequest, allowAll bool, failCode string, apply func(context.Context, *device.Runtime, string) error) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	if err := validateAppID(req.AppId); err != nil {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", err), nil
+	}
+	perms, err := resolvePermissions(req.Permission, allowAll)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "PERMISSION_UNSUPPORTED", err), nil
+	}
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.timeoutContext(ctx, req.Options, s.cfg.AppTimeout)
+	defer cancel()
+
+	out, err := runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		// Apps only declare some of the mapped permissions; pm rejects the rest,
+		// which is fine as long as at least one applied.
+		var applied []string
+		var errs []error
+		for _, perm := range perms {
+			if applyErr := apply(runCtx, runtime, perm); applyErr != nil {
+				errs = append(errs, applyErr)
+				continue
+			}
+			applied = append(applied, perm)
+		}
+		if len(applied) == 0 {
+			return nil, errors.Join(errs...)
+		}
+		return applied, nil
+	})
+	if err != nil {
+		return actionFailed(req.DeviceId, start, failCode, err), nil
+	}
+	resp := actionOK(req.DeviceId, start)
+	resp.Metadata["permissions"] = strings.Join(out.([]string), ",")
+	return resp, nil
+}
+
+func resolvePermissions(p mobilev1.Permission, allowAll bool) ([]string, error) {
+	if p == mobilev1.Permission_PERMISSION_UNSPECIFIED {
+		if !allowAll {
+			return nil, errors.New("permission is required")
+		}
+		seen := map[string]bool{}
+		all := make([]string, 0, 16)
+		for _, perms := range runtimePermissions {
+			for _, perm := range perms {
+				if !seen[perm] {
+					seen[perm] = true
+					all = append(all, perm)
+				}
+			}
+		}
+		sort.Strings(all)
+		return all, nil
+	}
+	perms, ok := runtimePermissions[p]
+	if !ok {
+		return nil, fmt.Errorf("permission %s has no android mapping", p)
+	}
+	return perms, nil
+}
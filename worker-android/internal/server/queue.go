@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var queueLanes = map[executor.Lane]mobilev1.QueueLane{
+	executor.LaneInteractive: mobilev1.QueueLane_QUEUE_LANE_INTERACTIVE,
+	executor.LaneQuery:       mobilev1.QueueLane_QUEUE_LANE_QUERY,
+	executor.LaneBackground:  mobilev1.QueueLane_QUEUE_LANE_BACKGROUND,
+}
+
+// GetQueueStats reports how many jobs wait in each executor lane. It reads the
+// queues directly instead of submitting a job, so it answers even when the
+// device is busy.
+func (s *MobileService) GetQueueStats(ctx context.Context, req *mobilev1.GetQueueStatsRequest) (*mobilev1.QueueStats, error) {
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	out := &mobilev1.QueueStats{DeviceId: req.DeviceId}
+	for _, lane := range executor.Lanes() {
+		out.Lanes = append(out.Lanes, &mobilev1.LaneDepth{
+			Lane:  queueLanes[lane],
+			Depth: uint32(runtime.Executor.Depth(lane)),
+		})
+	}
+	return out, nil
+}
+
+// AccountQueue is a unary interceptor that records how long the call's executor
+// jobs waited and ran. Both are returned as queue-wait-ms and device-ms
+// trailers and, for actions, in the response metadata. A call turned away by a
+// full lane before any of its jobs ran fails with RESOURCE_EXHAUSTED and a
+// retry-after-ms trailer, even if the handler folded the error into an
+// ActionResponse, since retrying it is safe. A rejection after a job ran keeps
+// the handler's own result.
+func (s *MobileService) AccountQueue(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, timing := executor.WithTiming(ctx)
+	resp, err := handler(ctx, req)
+	if full := timing.Rejected(); full != nil {
+		_ = grpc.SetTrailer(ctx, retryTrailer(full))
+		return nil, status.Error(codes.ResourceExhausted, full.Error())
+	}
+	wait, run, jobs := timing.Totals()
+	if jobs == 0 {
+		return resp, err
+	}
+	_ = grpc.SetTrailer(ctx, timingTrailer(wait, run))
+	if action, ok := resp.(*mobilev1.ActionResponse); ok && action != nil {
+		if action.Metadata == nil {
+			action.Metadata = map[string]string{}
+		}
+		action.Metadata["queue_wait_ms"] = strconv.FormatInt(wait.Milliseconds(), 10)
+		action.Metadata["device_ms"] = strconv.FormatInt(run.Milliseconds(), 10)
+	}
+	return resp, err
+}
+
+// AccountQueueStream is the streaming counterpart of AccountQueue; totals cover
+// every job the stream submitted.
+func (s *MobileService) AccountQueueStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, timing := executor.WithTiming(ss.Context())
+	err := handler(srv, &timedStream{ServerStream: ss, ctx: ctx})
+	if full := timing.Rejected(); full != nil {
+		ss.SetTrailer(retryTrailer(full))
+		return status.Error(codes.ResourceExhausted, full.Error())
+	}
+	if wait, run, jobs := timing.Totals(); jobs > 0 {
+		ss.SetTrailer(timingTrailer(wait, run))
+	}
+	return err
+}
+
+type timedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (t *timedStream) Context() context.Context {
+	return t.ctx
+}
+
+func timingTrailer(wait, run time.Duration) metadata.MD {
+	return metadata.Pairs(
+		"queue-wait-ms", strconv.FormatInt(wait.Milliseconds(), 10),
+		"device-ms", strconv.FormatInt(run.Milliseconds(), 10),
+	)
+}
+
+// streamStatus maps the error that ended a stream to a status: a full executor
+// lane is RESOURCE_EXHAUSTED, anything else Internal.
+func streamStatus(err error) error {
+	var full *executor.QueueFullError
+	if errors.As(err, &full) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func retryTrailer(full *executor.QueueFullError) metadata.MD {
+	return metadata.Pairs("retry-after-ms", strconv.FormatInt(full.RetryAfter.Milliseconds(), 10))
+}
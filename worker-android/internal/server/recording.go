@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/worker-android/internal/android"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Stopping waits for screenrecord to finalize and pulls every segment.
+const recordingStopTimeout = 2 * time.Minute
+
+type activeRecording struct {
+	id        string
+	startedAt time.Time
+	dir       string
+	handle    *android.ScreenRecording
+}
+
+// recordingSet tracks the one recording each device may have running.
+type recordingSet struct {
+	mu     sync.Mutex
+	active map[string]*activeRecording
+}
+
+func newRecordingSet() *recordingSet {
+	return &recordingSet{active: make(map[string]*activeRecording)}
+}
+
+// stopRecordings finalizes every running recording so shutdown does not leave
+// a recorder running or a file without its trailer. Files stay under
+// RECORDING_DIR.
+func (s *MobileService) stopRecordings() {
+	s.recordings.mu.Lock()
+	active := s.recordings.active
+	s.recordings.active = make(map[string]*activeRecording)
+	s.recordings.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), recordingStopTimeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for deviceID, rec := range active {
+		wg.Add(1)
+		go func(deviceID string, rec *activeRecording) {
+			defer wg.Done()
+			if _, err := rec.handle.Stop(ctx, rec.dir); err != nil {
+				s.log.Warn("recording stop failed", "device_id", deviceID, "recording_id", rec.id, "err", err)
+			}
+		}(deviceID, rec)
+	}
+	wg.Wait()
+}
+
+func (s *MobileService) StartRecording(ctx context.Context, req *mobilev1.StartRecordingRequest) (*mobilev1.StartRecordingResponse, error) {
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	s.recordings.mu.Lock()
+	defer s.recordings.mu.Unlock()
+	if existing, ok := s.recordings.active[req.DeviceId]; ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "recording %s is already running", existing.id)
+	}
+
+	maxDuration := s.cfg.RecordingMaxDuration
+	if req.MaxDurationSec > 0 && time.Duration(req.MaxDurationSec)*time.Second < maxDuration {
+		maxDuration = time.Duration(req.MaxDurationSec) * time.Second
+	}
+	rec := &activeRecording{id: uuid.NewString(), startedAt: time.Now().UTC()}
+	rec.dir = filepath.Join(s.cfg.RecordingDir, rec.id)
+	if err := os.MkdirAll(rec.dir, 0o755); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	rec.handle = runtime.ADB.StartScreenRecord(rec.id, int(req.BitRateMbps), maxDuration)
+	s.recordings.active[req.DeviceId] = rec
+
+	return &mobilev1.StartRecordingResponse{
+		DeviceId:        req.DeviceId,
+		RecordingId:     rec.id,
+		StartedAtUnixMs: rec.startedAt.UnixMilli(),
+	}, nil
+}
+
+// StopRecording finalizes the device's recording and streams each segment back
+// as a meta event followed by its chunks. Files stay under RECORDING_DIR.
+func (s *MobileService) StopRecording(req *mobilev1.StopRecordingRequest, stream mobilev1.MobileAutomationService_StopRecordingServer) error {
+	s.recordings.mu.Lock()
+	rec, ok := s.recordings.active[req.DeviceId]
+	if ok && req.RecordingId != "" && req.RecordingId != rec.id {
+		ok = false
+	}
+	if ok {
+		delete(s.recordings.active, req.DeviceId)
+	}
+	s.recordings.mu.Unlock()
+	if !ok {
+		return status.Error(codes.NotFound, "no matching recording is running")
+	}
+
+	ctx, cancel := context.WithTimeout(stream.Context(), recordingStopTimeout)
+	defer cancel()
+	paths, err := rec.handle.Stop(ctx, rec.dir)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	for i, path := range paths {
+		if err := s.sendRecordingSegment(stream, rec.id, uint32(i), path); err != nil {
+			return err
+		}
+	}
+	return stream.Send(&mobilev1.RecordingEvent{
+		Payload: &mobilev1.RecordingEvent_End{End: &mobilev1.StreamEnd{Reason: "recording_complete"}},
+	})
+}
+
+func (s *MobileService) sendRecordingSegment(stream mobilev1.MobileAutomationService_StopRecordingServer, recordingID string, index uint32, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	chunkSize := s.cfg.StreamChunkBytes
+	if chunkSize <= 0 {
+		chunkSize = 65536
+	}
+	chunkCount := int((info.Size() + int64(chunkSize) - 1) / int64(chunkSize))
+	if err := stream.Send(&mobilev1.RecordingEvent{
+		Payload: &mobilev1.RecordingEvent_SegmentMeta{SegmentMeta: &mobilev1.RecordingSegmentMeta{
+			RecordingId:  recordingID,
+			SegmentIndex: index,
+			MimeType:     "video/mp4",
+			TotalBytes:   uint64(info.Size()),
+			ChunkCount:   uint32(chunkCount),
+			HostPath:     path,
+		}},
+	}); err != nil {
+		return err
+	}
+
+	buf := make([]byte, chunkSize)
+	for i := 0; i < chunkCount; i++ {
+		n, err := io.ReadFull(file, buf)
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if err := stream.Send(&mobilev1.RecordingEvent{
+			Payload: &mobilev1.RecordingEvent_Chunk{Chunk: &mobilev1.RecordingChunk{
+				RecordingId:  recordingID,
+				SegmentIndex: index,
+				ChunkIndex:   uint32(i),
+				Data:         buf[:n],
+			}},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"image"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+	"github.com/fast-mobile-mcp/shared/imaging"
+	"github.com/fast-mobile-mcp/shared/snapshot"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errEmptyCrop = errors.New("crop region is empty")
+
+func (s *MobileService) TakeScreenshot(ctx context.Context, req *mobilev1.TakeScreenshotRequest) (*mobilev1.TakeScreenshotResponse, error) {
+	if crop := req.GetCrop(); crop != nil && (crop.Right <= crop.Left || crop.Bottom <= crop.Top) {
+		return nil, status.Error(codes.InvalidArgument, errEmptyCrop.Error())
+	}
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	var region *mobilev1.Bounds
+	switch {
+	case req.GetCrop() != nil:
+		region = req.GetCrop()
+	case req.GetRefId() != "":
+		_, node, err := s.resolveTarget(ctx, req.DeviceId, req.SnapshotId, req.GetRefId(), nil, nil)
+		if err != nil {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		region = convertBounds(node.Bounds)
+	}
+
+	out, err := runtime.Executor.Submit(ctx, executor.LaneQuery, func(runCtx context.Context) (any, error) {
+		data, _, _, err := runtime.UIA2.Screenshot(runCtx)
+		return data, err
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	capturedAt := time.Now().UTC()
+
+	img, format, err := encodeScreenshot(out.([]byte), req.Format, req.JpegQuality, region)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &mobilev1.TakeScreenshotResponse{
+		DeviceId:         req.DeviceId,
+		Data:             img.data,
+		MimeType:         format.MimeType(),
+		Width:            uint32(img.width),
+		Height:           uint32(img.height),
+		Region:           img.region,
+		CapturedAtUnixMs: capturedAt.UnixMilli(),
+		Scale:            1,
+	}, nil
+}
+
+// GetSnapshotScreenshot returns the screenshot captured with a GetUITree
+// snapshot taken with include_screenshot.
+func (s *MobileService) GetSnapshotScreenshot(ctx context.Context, req *mobilev1.GetSnapshotScreenshotRequest) (*mobilev1.TakeScreenshotResponse, error) {
+	snap, ok := s.store.Get(req.SnapshotId)
+	if !ok || snap.DeviceID != req.DeviceId {
+		return nil, status.Error(codes.NotFound, "snapshot not found or expired")
+	}
+	if snap.Screenshot == nil {
+		return nil, status.Error(codes.NotFound, "snapshot has no screenshot; request it with include_screenshot")
+	}
+	shot := snap.Screenshot
+	return &mobilev1.TakeScreenshotResponse{
+		DeviceId:         req.DeviceId,
+		Data:             shot.Data,
+		MimeType:         shot.MimeType,
+		Width:            uint32(shot.Width),
+		Height:           uint32(shot.Height),
+		Region:           &mobilev1.Bounds{Right: int32(shot.Width), Bottom: int32(shot.Height)},
+		CapturedAtUnixMs: shot.CapturedAt.UnixMilli(),
+		Scale:            1,
+	}, nil
+}
+
+func screenshotInfo(shot *snapshot.Screenshot) *mobilev1.SnapshotScreenshotInfo {
+	if shot == nil {
+		return nil
+	}
+	return &mobilev1.SnapshotScreenshotInfo{
+		MimeType:         shot.MimeType,
+		Width:            uint32(shot.Width),
+		Height:           uint32(shot.Height),
+		CapturedAtUnixMs: shot.CapturedAt.UnixMilli(),
+	}
+}
+
+type encodedImage struct {
+	data   []byte
+	width  int
+	height int
+	region *mobilev1.Bounds
+}
+
+// encodeScreenshot crops raw to region (in image pixels) and encodes it in the
+// requested format. An uncropped image already in that format is passed
+// through untouched.
+func encodeScreenshot(raw []byte, requested mobilev1.ImageFormat, quality uint32, region *mobilev1.Bounds) (encodedImage, imaging.Format, error) {
+	format := imaging.PNG
+	if requested == mobilev1.ImageFormat_IMAGE_FORMAT_JPEG {
+		format = imaging.JPEG
+	}
+
+	img, sourceFormat, err := imaging.Decode(raw)
+	if err != nil {
+		return encodedImage{}, "", err
+	}
+	full := img.Bounds()
+	if region == nil && sourceFormat == format {
+		return encodedImage{data: raw, width: full.Dx(), height: full.Dy(), region: boundsFromRect(full)}, format, nil
+	}
+	if region != nil {
+		img, err = imaging.Crop(img, image.Rect(int(region.Left), int(region.Top), int(region.Right), int(region.Bottom)))
+		if err != nil {
+			return encodedImage{}, "", err
+		}
+	}
+	data, err := imaging.Encode(img, format, int(quality))
+	if err != nil {
+		return encodedImage{}, "", err
+	}
+	b := img.Bounds()
+	return encodedImage{data: data, width: b.Dx(), height: b.Dy(), region: boundsFromRect(b)}, format, nil
+}
+
+func boundsFromRect(r image.Rectangle) *mobilev1.Bounds {
+	return &mobilev1.Bounds{Left: int32(r.Min.X), Top: int32(r.Min.Y), Right: int32(r.Max.X), Bottom: int32(r.Max.Y)}
+}
@@ -1,15 +1,22 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"image"
 	"log/slog"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+	"github.com/fast-mobile-mcp/shared/framestream"
+	"github.com/fast-mobile-mcp/shared/imaging"
 	"github.com/fast-mobile-mcp/shared/snapshot"
 	"github.com/fast-mobile-mcp/worker-android/internal/android"
 	"github.com/fast-mobile-mcp/worker-android/internal/config"
@@ -21,22 +28,35 @@ import (
 
 type MobileService struct {
 	mobilev1.UnimplementedMobileAutomationServiceServer
-	cfg      config.Config
-	log      *slog.Logger
-	registry *device.Registry
-	store    *snapshot.Store
+	cfg        config.Config
+	log        *slog.Logger
+	registry   *device.Registry
+	store      *snapshot.Store
+	watchers   *watcherSet
+	recordings *recordingSet
+	apps       *appTracker
+	actions    *actionLog
+
+	shellAllowlist []*regexp.Regexp
 }
 
 func NewMobileService(cfg config.Config, log *slog.Logger) *MobileService {
 	return &MobileService{
-		cfg:      cfg,
-		log:      log,
-		registry: device.NewRegistry(cfg),
-		store:    snapshot.NewStore(cfg.SnapshotTTL, cfg.SnapshotCleanup, cfg.MaxSnapshotsPerDevice),
+		cfg:        cfg,
+		log:        log,
+		registry:   device.NewRegistry(cfg),
+		store:      snapshot.NewStore(cfg.SnapshotTTL, cfg.SnapshotCleanup, cfg.MaxSnapshotsPerDevice),
+		watchers:   newWatcherSet(),
+		recordings: newRecordingSet(),
+		apps:       newAppTracker(),
+		actions:    newActionLog(),
+
+		shellAllowlist: compileAllowlist(cfg.ShellAllowlist, log),
 	}
 }
 
 func (s *MobileService) Close() {
+	s.stopRecordings()
 	s.registry.Close()
 	s.store.Close()
 }
@@ -108,7 +128,7 @@ func (s *MobileService) GetActiveApp(ctx context.Context, req *mobilev1.GetActiv
 	ctx, cancel := s.actionContext(ctx, req.Options)
 	defer cancel()
 
-	result, err := runtime.Executor.Submit(ctx, func(runCtx context.Context) (any, error) {
+	result, err := runtime.Executor.Submit(ctx, executor.LaneQuery, func(runCtx context.Context) (any, error) {
 		return runtime.UIA2.GetActiveApp(runCtx)
 	})
 	if err != nil {
@@ -133,19 +153,53 @@ func (s *MobileService) GetUITree(ctx context.Context, req *mobilev1.GetUITreeRe
 	ctx, cancel := s.actionContext(ctx, req.Options)
 	defer cancel()
 
-	nodesAny, err := runtime.Executor.Submit(ctx, func(runCtx context.Context) (any, error) {
-		return runtime.UIA2.DumpHierarchy(runCtx)
+	out, err := runtime.Executor.Submit(ctx, executor.LaneQuery, func(runCtx context.Context) (any, error) {
+		nodes, dumpErr := s.dumpHierarchy(runCtx, runtime)
+		if dumpErr != nil {
+			return nil, dumpErr
+		}
+		// Orientation is advisory for interpreting bounds; a failed lookup
+		// should not cost the caller the tree.
+		raw, _ := runtime.UIA2.Orientation(runCtx)
+		capture := treeCapture{nodes: nodes, orientation: orientationFromBackend(raw)}
+		if req.IncludeScreenshot {
+			// Taken in the same job, back to back with the dump, so nothing
+			// else can change the screen in between.
+			data, _, _, shotErr := runtime.UIA2.Screenshot(runCtx)
+			if shotErr != nil {
+				return nil, shotErr
+			}
+			capture.screenshot = data
+			capture.screenshotAt = time.Now().UTC()
+		}
+		return capture, nil
 	})
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	nodes := nodesAny.([]snapshot.Node)
+	capture := out.(treeCapture)
+	nodes := capture.nodes
 	if req.DepthLimit > 0 {
 		nodes = pruneByDepth(nodes, int(req.DepthLimit))
 	}
 
-	snap := s.store.Put(req.DeviceId, nodes)
+	var shot *snapshot.Screenshot
+	if capture.screenshot != nil {
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(capture.screenshot))
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		shot = &snapshot.Screenshot{
+			Data:       capture.screenshot,
+			MimeType:   imaging.Format(format).MimeType(),
+			Width:      cfg.Width,
+			Height:     cfg.Height,
+			CapturedAt: capture.screenshotAt,
+		}
+	}
+
+	snap := s.store.PutWithScreenshot(req.DeviceId, nodes, shot)
 	limit := int(req.NodeLimit)
 	if limit <= 0 {
 		limit = 300
@@ -162,6 +216,8 @@ func (s *MobileService) GetUITree(ctx context.Context, req *mobilev1.GetUITreeRe
 		Nodes:           convertNodes(paged),
 		TotalNodes:      uint32(total),
 		NextCursor:      next,
+		Orientation:     capture.orientation,
+		Screenshot:      screenshotInfo(shot),
 	}, nil
 }
 
@@ -217,23 +273,48 @@ func (s *MobileService) Tap(ctx context.Context, req *mobilev1.TapRequest) (*mob
 	ctx, cancel := s.actionContext(ctx, req.Options)
 	defer cancel()
 
-	point, resolveErr := s.resolveTargetPoint(ctx, req.DeviceId, req.SnapshotId, req.GetRefId(), req.GetSelector(), req.GetCoordinates())
+	point, node, resolveErr := s.resolveTarget(ctx, req.DeviceId, req.SnapshotId, req.GetRefId(), req.GetSelector(), req.GetCoordinates())
+	if resolveErr == nil {
+		resolveErr = validateCoordinates(req.CoordinateSpace, req.ScreenshotScale, req.GetCoordinates())
+	}
 	if resolveErr != nil {
 		return actionFailed(req.DeviceId, start, "INVALID_TARGET", resolveErr), nil
 	}
 
-	_, err = runtime.Executor.Submit(ctx, func(runCtx context.Context) (any, error) {
+	out, err := runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		if coords := req.GetCoordinates(); coords != nil {
+			converted, convErr := nativePoint(runCtx, runtime, req.CoordinateSpace, req.ScreenshotScale, coords)
+			if convErr != nil {
+				return nil, convErr
+			}
+			point = converted
+		}
+		if watchErr := s.runWatchers(runCtx, runtime); watchErr != nil {
+			return nil, watchErr
+		}
+		hidden := false
+		if req.HideKeyboardIfCovered {
+			var uncoverErr error
+			point, hidden, uncoverErr = uncoverTarget(runCtx, runtime, point, node)
+			if uncoverErr != nil {
+				return nil, uncoverErr
+			}
+		}
 		count := req.TapCount
 		if count <= 0 {
 			count = 1
 		}
-		return nil, runtime.UIA2.Tap(runCtx, point.x, point.y, count)
+		return hidden, runtime.UIA2.Tap(runCtx, point.x, point.y, count)
 	})
 	if err != nil {
 		return actionFailed(req.DeviceId, start, "TAP_FAILED", err), nil
 	}
 
-	return actionOK(req.DeviceId, start), nil
+	resp := actionOK(req.DeviceId, start)
+	if hidden, _ := out.(bool); hidden {
+		resp.Metadata["keyboard_hidden"] = "true"
+	}
+	return resp, nil
 }
 
 func (s *MobileService) Type(ctx context.Context, req *mobilev1.TypeRequest) (*mobilev1.ActionResponse, error) {
@@ -246,13 +327,29 @@ func (s *MobileService) Type(ctx context.Context, req *mobilev1.TypeRequest) (*m
 	ctx, cancel := s.actionContext(ctx, req.Options)
 	defer cancel()
 
-	if _, resolveErr := s.resolveTargetPoint(ctx, req.DeviceId, req.SnapshotId, req.GetRefId(), req.GetSelector(), req.GetCoordinates()); resolveErr != nil {
+	point, resolveErr := s.resolveTargetPoint(ctx, req.DeviceId, req.SnapshotId, req.GetRefId(), req.GetSelector(), req.GetCoordinates())
+	if resolveErr != nil {
 		return actionFailed(req.DeviceId, start, "INVALID_TARGET", resolveErr), nil
 	}
 
-	_, err = runtime.Executor.Submit(ctx, func(runCtx context.Context) (any, error) {
+	_, err = runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		if watchErr := s.runWatchers(runCtx, runtime); watchErr != nil {
+			return nil, watchErr
+		}
+		if focusErr := focusTarget(runCtx, runtime, point); focusErr != nil {
+			return nil, focusErr
+		}
+		if req.ReplaceText {
+			return nil, replaceText(runCtx, runtime, point, req.Text)
+		}
 		return nil, runtime.UIA2.Type(runCtx, req.Text, req.ClearBeforeType)
 	})
+	if errors.Is(err, errNotFocused) {
+		return actionFailed(req.DeviceId, start, "FOCUS_FAILED", err), nil
+	}
+	if errors.Is(err, errReplaceMismatch) {
+		return actionFailed(req.DeviceId, start, "REPLACE_MISMATCH", err), nil
+	}
 	if err != nil {
 		return actionFailed(req.DeviceId, start, "TYPE_FAILED", err), nil
 	}
@@ -268,14 +365,37 @@ func (s *MobileService) Swipe(ctx context.Context, req *mobilev1.SwipeRequest) (
 	ctx, cancel := s.actionContext(ctx, req.Options)
 	defer cancel()
 
-	sx, sy, ex, ey := swipeCoordinates(req)
+	if err := validateCoordinates(req.CoordinateSpace, req.ScreenshotScale, req.Start, req.End); err != nil {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", err), nil
+	}
 	duration := req.DurationMs
 	if duration <= 0 {
 		duration = 200
 	}
 
-	_, err = runtime.Executor.Submit(ctx, func(runCtx context.Context) (any, error) {
-		return nil, runtime.UIA2.Swipe(runCtx, sx, sy, ex, ey, duration)
+	_, err = runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		// Explicit endpoints may be in another space; direction swipes are
+		// placed on the measured screen in native units.
+		var from, to point
+		if req.Start != nil && req.End != nil {
+			var convErr error
+			if from, convErr = nativePoint(runCtx, runtime, req.CoordinateSpace, req.ScreenshotScale, req.Start); convErr != nil {
+				return nil, convErr
+			}
+			if to, convErr = nativePoint(runCtx, runtime, req.CoordinateSpace, req.ScreenshotScale, req.End); convErr != nil {
+				return nil, convErr
+			}
+		} else {
+			c, measureErr := currentCoordinates(runCtx, runtime)
+			if measureErr != nil {
+				return nil, measureErr
+			}
+			from, to = directionSwipe(c, req.Direction, req.DistancePx)
+		}
+		if watchErr := s.runWatchers(runCtx, runtime); watchErr != nil {
+			return nil, watchErr
+		}
+		return nil, runtime.UIA2.Swipe(runCtx, from.x, from.y, to.x, to.y, duration)
 	})
 	if err != nil {
 		return actionFailed(req.DeviceId, start, "SWIPE_FAILED", err), nil
@@ -296,16 +416,60 @@ func (s *MobileService) ScreenshotStream(req *mobilev1.ScreenshotStreamRequest,
 	if fps > s.cfg.StreamMaxFPS {
 		fps = s.cfg.StreamMaxFPS
 	}
-	interval := time.Second / time.Duration(fps)
 	chunkSize := s.cfg.StreamChunkBytes
 	if chunkSize <= 0 {
 		chunkSize = 65536
 	}
 
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	// Taps use device pixels, so frames only rescale when downsized.
+	const deviceScale = 1.0
+
+	// Capture runs ahead of the stream and only ever leaves the newest frame
+	// waiting, so a slow consumer costs dropped frames rather than a backlog of
+	// executor jobs.
+	var dedupe *framestream.Deduper
+	if req.SkipUnchanged {
+		dedupe = framestream.NewDeduper(int(req.ChangeThreshold))
+	}
+	producer := framestream.NewProducer(
+		framestream.NewPacer(time.Second/time.Duration(fps)),
+		dedupe,
+		func(runCtx context.Context) ([]byte, error) {
+			out, err := runtime.Executor.Submit(runCtx, executor.LaneBackground, func(jobCtx context.Context) (any, error) {
+				data, _, _, capErr := runtime.UIA2.Screenshot(jobCtx)
+				return data, capErr
+			})
+			// A full lane means other work is queued; skip this tick rather
+			// than end a healthy stream.
+			var full *executor.QueueFullError
+			if errors.As(err, &full) {
+				select {
+				case <-runCtx.Done():
+					return nil, runCtx.Err()
+				case <-time.After(full.RetryAfter):
+				}
+				return nil, framestream.ErrBusy
+			}
+			if err != nil {
+				return nil, err
+			}
+			return out.([]byte), nil
+		},
+		func(raw []byte, img image.Image) (framestream.Image, error) {
+			return framestream.Encode(raw, img, int(req.JpegQuality), int(req.MaxWidth), int(req.MaxHeight))
+		},
+	)
+	go producer.Run(ctx)
+
+	heartbeat := time.NewTicker(framestream.HeartbeatInterval)
+	defer heartbeat.Stop()
 
 	framesSent := uint32(0)
+	sentSinceHeartbeat := 0
+	lastHeartbeat := time.Now()
 	for {
 		if req.MaxFrames > 0 && framesSent >= req.MaxFrames {
 			_ = stream.Send(&mobilev1.ScreenshotStreamEvent{
@@ -317,40 +481,37 @@ func (s *MobileService) ScreenshotStream(req *mobilev1.ScreenshotStreamRequest,
 		select {
 		case <-stream.Context().Done():
 			return stream.Context().Err()
-		case <-ticker.C:
-			out, err := runtime.Executor.Submit(stream.Context(), func(runCtx context.Context) (any, error) {
-				data, w, h, capErr := runtime.UIA2.Screenshot(runCtx)
-				if capErr != nil {
-					return nil, capErr
-				}
-				return struct {
-					data []byte
-					w    int32
-					h    int32
-				}{data: data, w: w, h: h}, nil
-			})
-			if err != nil {
-				return status.Error(codes.Internal, err.Error())
+		case err := <-producer.Errs():
+			return streamStatus(err)
+		case now := <-heartbeat.C:
+			effective := float32(sentSinceHeartbeat) / float32(now.Sub(lastHeartbeat).Seconds())
+			sentSinceHeartbeat, lastHeartbeat = 0, now
+			if err := stream.Send(&mobilev1.ScreenshotStreamEvent{
+				Payload: &mobilev1.ScreenshotStreamEvent_Heartbeat{Heartbeat: &mobilev1.StreamHeartbeat{
+					UnixMs:        now.UTC().UnixMilli(),
+					FramesSkipped: producer.Skipped(),
+					EffectiveFps:  effective,
+					FramesDropped: producer.Dropped(),
+				}},
+			}); err != nil {
+				return err
 			}
-
-			frame := out.(struct {
-				data []byte
-				w    int32
-				h    int32
-			})
+		case frame := <-producer.Frames():
+			sendStart := time.Now()
 			frameID := uuid.NewString()
-			chunkCount := int((len(frame.data) + chunkSize - 1) / chunkSize)
+			chunkCount := int((len(frame.Data) + chunkSize - 1) / chunkSize)
 
 			if err := stream.Send(&mobilev1.ScreenshotStreamEvent{
 				Payload: &mobilev1.ScreenshotStreamEvent_FrameMeta{FrameMeta: &mobilev1.ScreenshotFrameMeta{
 					FrameId:          frameID,
 					DeviceId:         req.DeviceId,
-					Width:            uint32(frame.w),
-					Height:           uint32(frame.h),
-					MimeType:         "image/jpeg",
-					TotalBytes:       uint64(len(frame.data)),
+					Width:            uint32(frame.Width),
+					Height:           uint32(frame.Height),
+					MimeType:         frame.Format.MimeType(),
+					TotalBytes:       uint64(len(frame.Data)),
 					ChunkCount:       uint32(chunkCount),
-					CapturedAtUnixMs: time.Now().UTC().UnixMilli(),
+					CapturedAtUnixMs: frame.CapturedAt.UnixMilli(),
+					Scale:            frame.Scale(deviceScale),
 				}},
 			}); err != nil {
 				return err
@@ -359,57 +520,163 @@ func (s *MobileService) ScreenshotStream(req *mobilev1.ScreenshotStreamRequest,
 			for i := 0; i < chunkCount; i++ {
 				start := i * chunkSize
 				end := start + chunkSize
-				if end > len(frame.data) {
-					end = len(frame.data)
+				if end > len(frame.Data) {
+					end = len(frame.Data)
 				}
 				if err := stream.Send(&mobilev1.ScreenshotStreamEvent{
 					Payload: &mobilev1.ScreenshotStreamEvent_Chunk{Chunk: &mobilev1.ScreenshotChunk{
 						FrameId:    frameID,
 						ChunkIndex: uint32(i),
-						Data:       frame.data[start:end],
+						Data:       frame.Data[start:end],
 					}},
 				}); err != nil {
 					return err
 				}
 			}
 
+			producer.Pacer().ObserveSend(time.Since(sendStart))
 			framesSent++
+			sentSinceHeartbeat++
 		}
 	}
 }
 
+type treeCapture struct {
+	nodes        []snapshot.Node
+	orientation  mobilev1.Orientation
+	screenshot   []byte
+	screenshotAt time.Time
+}
+
 type point struct {
 	x int32
 	y int32
 }
 
 func (s *MobileService) resolveTargetPoint(ctx context.Context, deviceID, snapshotID, refID string, selector *mobilev1.Selector, coords *mobilev1.Coordinates) (point, error) {
+	p, _, err := s.resolveTarget(ctx, deviceID, snapshotID, refID, selector, coords)
+	return p, err
+}
+
+// resolveTarget also returns the snapshot node behind the point, or nil when the
+// caller passed raw coordinates.
+func (s *MobileService) resolveTarget(ctx context.Context, deviceID, snapshotID, refID string, selector *mobilev1.Selector, coords *mobilev1.Coordinates) (point, *snapshot.Node, error) {
 	if coords != nil {
-		return point{x: coords.X, y: coords.Y}, nil
+		return point{x: coords.X, y: coords.Y}, nil, nil
 	}
 
 	snap, err := s.resolveSnapshot(ctx, deviceID, snapshotID)
 	if err != nil {
-		return point{}, err
+		return point{}, nil, err
 	}
 
 	if refID != "" {
 		n, ok := s.store.ResolveRef(snap.ID, refID)
 		if !ok {
-			return point{}, fmt.Errorf("ref_id %s not found", refID)
+			return point{}, nil, fmt.Errorf("ref_id %s not found", refID)
 		}
-		return center(n.Bounds), nil
+		return center(n.Bounds), &n, nil
 	}
 
 	if selector != nil {
 		matches := filterNodes(snap.Nodes, selector)
 		if len(matches) == 0 {
-			return point{}, fmt.Errorf("selector matched zero nodes")
+			return point{}, nil, fmt.Errorf("selector matched zero nodes")
 		}
-		return center(matches[0].Bounds), nil
+		return center(matches[0].Bounds), &matches[0], nil
 	}
 
-	return point{}, fmt.Errorf("missing action target")
+	return point{}, nil, fmt.Errorf("missing action target")
+}
+
+// relocate finds node in a fresh dump by its identifying attributes, since
+// ref_ids are only stable within one snapshot.
+func relocate(node snapshot.Node, nodes []snapshot.Node) (snapshot.Node, bool) {
+	if node.ResourceID == "" && node.Text == "" && node.ContentDesc == "" {
+		return snapshot.Node{}, false
+	}
+	for _, n := range nodes {
+		if n.ResourceID == node.ResourceID && n.Text == node.Text && n.ContentDesc == node.ContentDesc && n.ClassName == node.ClassName {
+			return n, true
+		}
+	}
+	return snapshot.Node{}, false
+}
+
+const (
+	focusPollAttempts = 5
+	focusPollInterval = 80 * time.Millisecond
+)
+
+var errNotFocused = errors.New("target did not take input focus")
+
+// focusTarget taps the resolved point and waits until the node under it reports
+// input focus, so keystrokes land in the intended field rather than wherever
+// focus happened to be.
+func focusTarget(ctx context.Context, runtime *device.Runtime, p point) error {
+	if err := runtime.UIA2.Tap(ctx, p.x, p.y, 1); err != nil {
+		return err
+	}
+	for attempt := 1; ; attempt++ {
+		nodes, err := runtime.UIA2.DumpHierarchy(ctx)
+		if err != nil {
+			return err
+		}
+		if focusedAt(nodes, p) {
+			return nil
+		}
+		if attempt >= focusPollAttempts {
+			return fmt.Errorf("%w at (%d,%d)", errNotFocused, p.x, p.y)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(focusPollInterval):
+		}
+	}
+}
+
+func focusedAt(nodes []snapshot.Node, p point) bool {
+	for _, n := range nodes {
+		if n.Focused && contains(n.Bounds, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceText clears the focused field, types text and reads the field back from
+// a fresh hierarchy dump to confirm it now holds exactly the requested value.
+func replaceText(ctx context.Context, runtime *device.Runtime, p point, text string) error {
+	if err := runtime.UIA2.Type(ctx, text, true); err != nil {
+		return err
+	}
+	nodes, err := runtime.UIA2.DumpHierarchy(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		if n.Focused && contains(n.Bounds, p) {
+			if !textMatches(text, n.Text) {
+				return fmt.Errorf("%w: field reads %q", errReplaceMismatch, n.Text)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: field lost focus", errReplaceMismatch)
+}
+
+var errReplaceMismatch = errors.New("field value does not match replacement text")
+
+// textMatches compares a read-back field value with the text that was typed.
+// Secure fields echo bullets instead of their content, so a masked value of the
+// right length is accepted.
+func textMatches(want, got string) bool {
+	if want == got {
+		return true
+	}
+	masked := strings.Trim(got, "•*●")
+	return masked == "" && utf8.RuneCountInString(got) == utf8.RuneCountInString(want)
 }
 
 func (s *MobileService) resolveSnapshot(ctx context.Context, deviceID, snapshotID string) (snapshot.Snapshot, error) {
@@ -428,8 +695,8 @@ func (s *MobileService) resolveSnapshot(ctx context.Context, deviceID, snapshotI
 		return snapshot.Snapshot{}, err
 	}
 
-	out, err := runtime.Executor.Submit(ctx, func(runCtx context.Context) (any, error) {
-		return runtime.UIA2.DumpHierarchy(runCtx)
+	out, err := runtime.Executor.Submit(ctx, executor.LaneQuery, func(runCtx context.Context) (any, error) {
+		return s.dumpHierarchy(runCtx, runtime)
 	})
 	if err != nil {
 		return snapshot.Snapshot{}, err
@@ -456,16 +723,21 @@ func convertNode(n snapshot.Node) *mobilev1.UiNode {
 		ResourceId:  n.ResourceID,
 		ClassName:   n.ClassName,
 		PackageName: n.PackageName,
-		Bounds:      &mobilev1.Bounds{Left: n.Bounds.Left, Top: n.Bounds.Top, Right: n.Bounds.Right, Bottom: n.Bounds.Bottom},
+		Bounds:      convertBounds(n.Bounds),
 		Enabled:     n.Enabled,
 		Clickable:   n.Clickable,
 		Focusable:   n.Focusable,
 		Visible:     n.Visible,
 		Selected:    n.Selected,
 		Checked:     n.Checked,
+		Focused:     n.Focused,
 	}
 }
 
+func convertBounds(b snapshot.Bounds) *mobilev1.Bounds {
+	return &mobilev1.Bounds{Left: b.Left, Top: b.Top, Right: b.Right, Bottom: b.Bottom}
+}
+
 func actionOK(deviceID string, startedAt time.Time) *mobilev1.ActionResponse {
 	return &mobilev1.ActionResponse{
 		DeviceId:          deviceID,
@@ -495,7 +767,13 @@ func actionFailed(deviceID string, startedAt time.Time, code string, err error)
 }
 
 func (s *MobileService) actionContext(parent context.Context, options *mobilev1.RequestOptions) (context.Context, context.CancelFunc) {
-	timeout := s.cfg.ActionTimeout
+	return s.timeoutContext(parent, options, s.cfg.ActionTimeout)
+}
+
+// timeoutContext applies the caller's timeout_ms when set and fallback otherwise,
+// for operations that are expected to outlast ACTION_TIMEOUT.
+func (s *MobileService) timeoutContext(parent context.Context, options *mobilev1.RequestOptions, fallback time.Duration) (context.Context, context.CancelFunc) {
+	timeout := fallback
 	if options != nil && options.TimeoutMs > 0 {
 		timeout = time.Duration(options.TimeoutMs) * time.Millisecond
 	}
@@ -534,6 +812,10 @@ func center(b snapshot.Bounds) point {
 	return point{x: (b.Left + b.Right) / 2, y: (b.Top + b.Bottom) / 2}
 }
 
+func contains(b snapshot.Bounds, p point) bool {
+	return p.x >= b.Left && p.x < b.Right && p.y >= b.Top && p.y < b.Bottom
+}
+
 var boolRegex = regexp.MustCompile(`^(true|false)$`)
 
 func filterNodes(nodes []snapshot.Node, selector *mobilev1.Selector) []snapshot.Node {
@@ -623,28 +905,3 @@ func compareWithOperator(actual string, op mobilev1.SelectorOperator, expected s
 		return false
 	}
 }
-
-func swipeCoordinates(req *mobilev1.SwipeRequest) (int32, int32, int32, int32) {
-	if req.Start != nil && req.End != nil {
-		return req.Start.X, req.Start.Y, req.End.X, req.End.Y
-	}
-	distance := req.DistancePx
-	if distance <= 0 {
-		distance = 400
-	}
-	sx, sy := int32(500), int32(1000)
-	ex, ey := sx, sy
-	switch req.Direction {
-	case mobilev1.Direction_DIRECTION_UP:
-		ey = sy - distance
-	case mobilev1.Direction_DIRECTION_DOWN:
-		ey = sy + distance
-	case mobilev1.Direction_DIRECTION_LEFT:
-		ex = sx - distance
-	case mobilev1.Direction_DIRECTION_RIGHT:
-		ex = sx + distance
-	default:
-		ey = sy - distance
-	}
-	return sx, sy, ex, ey
-}
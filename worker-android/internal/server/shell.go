@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+	"github.com/fast-mobile-mcp/worker-android/internal/android"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// compileAllowlist anchors each configured pattern so it must match the whole
+// command line; an invalid pattern is logged and skipped rather than widening
+// access.
+func compileAllowlist(patterns []string, log *slog.Logger) []*regexp.Regexp {
+	out := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(`^(?:` + p + `)$`)
+		if err != nil {
+			log.Warn("ignoring invalid shell allowlist pattern", "pattern", p, "err", err)
+			continue
+		}
+		out = append(out, re)
+	}
+	return out
+}
+
+func (s *MobileService) shellAllowed(commandLine string) bool {
+	for _, re := range s.shellAllowlist {
+		if re.MatchString(commandLine) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MobileService) ExecShell(ctx context.Context, req *mobilev1.ExecShellRequest) (*mobilev1.ExecShellResponse, error) {
+	if len(req.Argv) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "argv is required")
+	}
+	commandLine := strings.Join(req.Argv, " ")
+	if !s.shellAllowed(commandLine) {
+		return nil, status.Error(codes.PermissionDenied, fmt.Sprintf("command %q is not in SHELL_ALLOWLIST", commandLine))
+	}
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	ctx, cancel := s.timeoutContext(ctx, req.Options, s.cfg.ShellTimeout)
+	defer cancel()
+
+	start := time.Now()
+	out, err := runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		return runtime.ADB.ExecShell(runCtx, req.Argv, s.cfg.ShellMaxOutputBytes)
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	result := out.(android.ShellResult)
+	return &mobilev1.ExecShellResponse{
+		DeviceId:   req.DeviceId,
+		ExitCode:   int32(result.ExitCode),
+		Stdout:     result.Stdout,
+		Stderr:     result.Stderr,
+		Truncated:  result.Truncated,
+		DurationMs: time.Since(start).Milliseconds(),
+	}, nil
+}
@@ -23,8 +23,11 @@ func main() {
 		os.Exit(1)
 	}
 
-	grpcServer := grpc.NewServer()
 	svc := server.NewMobileService(cfg, logger)
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(svc.AccountQueue, svc.AttachCrashes),
+		grpc.StreamInterceptor(svc.AccountQueueStream),
+	)
 	mobilev1.RegisterMobileAutomationServiceServer(grpcServer, svc)
 
 	stop := make(chan os.Signal, 1)
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 )
@@ -12,9 +13,14 @@ type Config struct {
 	SnapshotTTL           time.Duration
 	SnapshotCleanup       time.Duration
 	MaxSnapshotsPerDevice int
+	ExecutorMaxQueueDepth int
 	ActionTimeout         time.Duration
+	AppTimeout            time.Duration
+	InstallTimeout        time.Duration
 	StreamChunkBytes      int
 	StreamMaxFPS          int
+	RecordingDir          string
+	RecordingMaxDuration  time.Duration
 	SimctlPath            string
 	WDABasePort           int
 	WDAHost               string
@@ -29,9 +35,14 @@ func Load() Config {
 		SnapshotTTL:           getDuration("SNAPSHOT_TTL", 30*time.Second),
 		SnapshotCleanup:       getDuration("SNAPSHOT_CLEANUP_INTERVAL", 10*time.Second),
 		MaxSnapshotsPerDevice: getInt("MAX_SNAPSHOTS_PER_DEVICE", 8),
+		ExecutorMaxQueueDepth: getInt("EXECUTOR_MAX_QUEUE_DEPTH", 64),
 		ActionTimeout:         getDuration("ACTION_TIMEOUT", 2*time.Second),
+		AppTimeout:            getDuration("APP_TIMEOUT", 20*time.Second),
+		InstallTimeout:        getDuration("INSTALL_TIMEOUT", 3*time.Minute),
 		StreamChunkBytes:      getInt("STREAM_CHUNK_BYTES", 65536),
 		StreamMaxFPS:          getInt("STREAM_MAX_FPS", 12),
+		RecordingDir:          getEnv("RECORDING_DIR", filepath.Join(os.TempDir(), "fast-mobile-mcp", "recordings")),
+		RecordingMaxDuration:  getDuration("RECORDING_MAX_DURATION", 30*time.Minute),
 		SimctlPath:            getEnv("SIMCTL_PATH", "xcrun"),
 		WDABasePort:           getInt("WDA_BASE_PORT", 8100),
 		WDAHost:               getEnv("WDA_HOST", "127.0.0.1"),
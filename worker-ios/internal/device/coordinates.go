@@ -0,0 +1,34 @@
+package device
+
+import "time"
+
+// Coordinates is a device's screen geometry in its current orientation.
+// Logical units are points on iOS and dp on Android; Scale is pixels per
+// logical unit.
+type Coordinates struct {
+	LogicalWidth  int32
+	LogicalHeight int32
+	PixelWidth    int32
+	PixelHeight   int32
+	Scale         float64
+	Orientation   string
+	MeasuredAt    time.Time
+}
+
+// SetCoordinates records a fresh measurement of the device's geometry.
+func (r *Runtime) SetCoordinates(c Coordinates) {
+	r.coordsMu.Lock()
+	defer r.coordsMu.Unlock()
+	r.coords = c
+}
+
+// CachedCoordinates returns the last measurement if it is younger than maxAge.
+// Rotation changes the geometry, so callers keep maxAge short.
+func (r *Runtime) CachedCoordinates(maxAge time.Duration) (Coordinates, bool) {
+	r.coordsMu.Lock()
+	defer r.coordsMu.Unlock()
+	if r.coords.MeasuredAt.IsZero() || time.Since(r.coords.MeasuredAt) > maxAge {
+		return Coordinates{}, false
+	}
+	return r.coords, true
+}
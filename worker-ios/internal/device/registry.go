@@ -5,14 +5,20 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/fast-mobile-mcp/shared/executor"
 	"github.com/fast-mobile-mcp/worker-ios/internal/config"
 	"github.com/fast-mobile-mcp/worker-ios/internal/ios"
 )
 
 type Runtime struct {
 	DeviceID string
-	Executor *Executor
+	Executor *executor.Executor
 	WDA      *ios.WDAClient
+	Simctl   *ios.SimctlClient
+	Crashes  *ios.CrashWatcher
+
+	coordsMu sync.Mutex
+	coords   Coordinates
 }
 
 type Registry struct {
@@ -55,18 +61,30 @@ func (r *Registry) RuntimeForDevice(ctx context.Context, deviceID string) (*Runt
 
 	runtime := &Runtime{
 		DeviceID: deviceID,
-		Executor: NewExecutor(256),
+		Executor: executor.NewExecutor(r.cfg.ExecutorMaxQueueDepth),
 		WDA:      client,
+		Simctl:   ios.NewSimctlClient(r.cfg.SimctlPath, deviceID),
+		Crashes:  ios.NewCrashWatcher(deviceID),
 	}
 	r.runtimes[deviceID] = runtime
 	return runtime, nil
 }
 
+// Lookup returns the runtime for a device that already has one, without
+// connecting to it.
+func (r *Registry) Lookup(deviceID string) (*Runtime, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	runtime, ok := r.runtimes[deviceID]
+	return runtime, ok
+}
+
 func (r *Registry) Close() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	for _, runtime := range r.runtimes {
 		runtime.Executor.Close()
+		runtime.Crashes.Close()
 	}
 }
 
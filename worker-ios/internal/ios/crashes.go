@@ -0,0 +1,237 @@
+package ios
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxPendingCrashes  = 32
+	maxCrashFrames     = 15
+	crashPollInterval  = time.Second
+	crashWriteTimeout  = 30 * time.Second
+	watchdogTerminator = "8badf00d"
+)
+
+// Crash is an app crash report written for the simulator. Kind is "crash", or
+// "anr" for watchdog terminations. App is the crashed app's bundle identifier.
+type Crash struct {
+	Time    time.Time
+	Kind    string
+	Process string
+	App     string
+	Excerpt string
+}
+
+// CrashWatcher polls the host's DiagnosticReports directory, where simulator
+// apps write their .ips crash reports, and keeps reports for this simulator
+// until they are collected with Take or Pending.
+type CrashWatcher struct {
+	udid   string
+	dir    string
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu      sync.Mutex
+	seen    map[string]bool
+	pending []Crash
+}
+
+type ipsReport struct {
+	ProcName    string `json:"procName"`
+	ProcPath    string `json:"procPath"`
+	CaptureTime string `json:"captureTime"`
+	BundleInfo  struct {
+		BundleID string `json:"CFBundleIdentifier"`
+	} `json:"bundleInfo"`
+	FaultingThread int `json:"faultingThread"`
+	Exception      struct {
+		Type   string `json:"type"`
+		Signal string `json:"signal"`
+	} `json:"exception"`
+	Termination struct {
+		Indicator string `json:"indicator"`
+	} `json:"termination"`
+	Threads []struct {
+		Frames []struct {
+			ImageIndex int    `json:"imageIndex"`
+			Symbol     string `json:"symbol"`
+		} `json:"frames"`
+	} `json:"threads"`
+	UsedImages []struct {
+		Name string `json:"name"`
+	} `json:"usedImages"`
+}
+
+const ipsTimeLayout = "2006-01-02 15:04:05.0000 -0700"
+
+func NewCrashWatcher(udid string) *CrashWatcher {
+	home, _ := os.UserHomeDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &CrashWatcher{
+		udid:   udid,
+		dir:    filepath.Join(home, "Library", "Logs", "DiagnosticReports"),
+		cancel: cancel,
+		done:   make(chan struct{}),
+		seen:   make(map[string]bool),
+	}
+	// Reports already on disk belong to earlier sessions.
+	w.scan(false)
+	go w.run(ctx)
+	return w
+}
+
+// Pending returns every crash not yet collected, oldest first.
+func (w *CrashWatcher) Pending() []Crash {
+	return w.Take(time.Time{}, time.Time{}, "")
+}
+
+// Take removes and returns the crashes captured between since and until for
+// the app with the given bundle identifier, oldest first. A zero bound or an
+// empty app matches anything. Crashes that do not match stay pending.
+func (w *CrashWatcher) Take(since, until time.Time, app string) []Crash {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var out []Crash
+	kept := w.pending[:0]
+	for _, crash := range w.pending {
+		if crashMatches(crash, since, until, app) {
+			out = append(out, crash)
+		} else {
+			kept = append(kept, crash)
+		}
+	}
+	clear(w.pending[len(kept):])
+	w.pending = kept
+	return out
+}
+
+func crashMatches(crash Crash, since, until time.Time, app string) bool {
+	if !since.IsZero() && crash.Time.Before(since) {
+		return false
+	}
+	if !until.IsZero() && crash.Time.After(until) {
+		return false
+	}
+	return app == "" || crash.App == app
+}
+
+// Sync reads any reports written since the last poll.
+func (w *CrashWatcher) Sync() {
+	w.scan(true)
+}
+
+func (w *CrashWatcher) Close() {
+	w.cancel()
+	<-w.done
+}
+
+func (w *CrashWatcher) run(ctx context.Context) {
+	defer close(w.done)
+	ticker := time.NewTicker(crashPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.scan(true)
+		}
+	}
+}
+
+func (w *CrashWatcher) scan(record bool) {
+	paths, _ := filepath.Glob(filepath.Join(w.dir, "*.ips"))
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, path := range paths {
+		if w.seen[path] {
+			continue
+		}
+		if !record {
+			w.seen[path] = true
+			continue
+		}
+		crash, ok, err := w.readReport(path)
+		if err != nil && !w.abandoned(path) {
+			// The report may still be being written; read it again next poll.
+			continue
+		}
+		w.seen[path] = true
+		if !ok {
+			continue
+		}
+		if len(w.pending) == maxPendingCrashes {
+			w.pending = w.pending[1:]
+		}
+		w.pending = append(w.pending, crash)
+	}
+}
+
+// abandoned reports whether a report that still fails to parse has gone long
+// enough without being written to that it never will.
+func (w *CrashWatcher) abandoned(path string) bool {
+	info, err := os.Stat(path)
+	return err != nil || time.Since(info.ModTime()) > crashWriteTimeout
+}
+
+// readReport parses an .ips file: a one-line JSON header followed by the JSON
+// report body. Reports from other simulators or the host are skipped. An error
+// means the file could not be read or parsed, usually because it is only
+// partly written.
+func (w *CrashWatcher) readReport(path string) (Crash, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Crash{}, false, err
+	}
+	_, body, ok := strings.Cut(string(data), "\n")
+	if !ok {
+		return Crash{}, false, fmt.Errorf("%s: missing report body", path)
+	}
+	var report ipsReport
+	if err := json.Unmarshal([]byte(body), &report); err != nil {
+		return Crash{}, false, fmt.Errorf("%s: %w", path, err)
+	}
+	if !strings.Contains(report.ProcPath, "/"+w.udid+"/") {
+		return Crash{}, false, nil
+	}
+
+	crash := Crash{Kind: "crash", Process: report.ProcName, App: report.BundleInfo.BundleID}
+	if ts, err := time.Parse(ipsTimeLayout, report.CaptureTime); err == nil {
+		crash.Time = ts.UTC()
+	} else if info, err := os.Stat(path); err == nil {
+		crash.Time = info.ModTime().UTC()
+	}
+	if strings.Contains(strings.ToLower(report.Termination.Indicator), watchdogTerminator) {
+		crash.Kind = "anr"
+	}
+
+	var lines []string
+	if report.Exception.Type != "" {
+		lines = append(lines, fmt.Sprintf("%s (%s)", report.Exception.Type, report.Exception.Signal))
+	}
+	if report.Termination.Indicator != "" {
+		lines = append(lines, "Termination: "+report.Termination.Indicator)
+	}
+	if report.FaultingThread >= 0 && report.FaultingThread < len(report.Threads) {
+		lines = append(lines, fmt.Sprintf("Thread %d Crashed:", report.FaultingThread))
+		for i, frame := range report.Threads[report.FaultingThread].Frames {
+			if i == maxCrashFrames {
+				break
+			}
+			image := "???"
+			if frame.ImageIndex >= 0 && frame.ImageIndex < len(report.UsedImages) {
+				image = report.UsedImages[frame.ImageIndex].Name
+			}
+			lines = append(lines, fmt.Sprintf("%-3d %s %s", i, image, frame.Symbol))
+		}
+	}
+	crash.Excerpt = strings.Join(lines, "\n")
+	return crash, true, nil
+}
@@ -0,0 +1,56 @@
+package ios
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// VideoRecording is a running `simctl io recordVideo` process.
+type VideoRecording struct {
+	cmd    *exec.Cmd
+	path   string
+	stderr bytes.Buffer
+	done   chan error
+	timer  *time.Timer
+}
+
+// StartRecording records the simulator screen to path until Stop is called or
+// maxDuration elapses.
+func (c *SimctlClient) StartRecording(path string, maxDuration time.Duration) (*VideoRecording, error) {
+	r := &VideoRecording{path: path, done: make(chan error, 1)}
+	r.cmd = exec.Command(c.xcrunPath, "simctl", "io", c.udid, "recordVideo", "--codec", "h264", "--force", path)
+	r.cmd.Stderr = &r.stderr
+	if err := r.cmd.Start(); err != nil {
+		return nil, err
+	}
+	go func() { r.done <- r.cmd.Wait() }()
+	r.timer = time.AfterFunc(maxDuration, r.interrupt)
+	return r, nil
+}
+
+// Stop interrupts recordVideo, which finalizes the file on SIGINT, and returns
+// its path.
+func (r *VideoRecording) Stop(ctx context.Context) (string, error) {
+	r.timer.Stop()
+	r.interrupt()
+	select {
+	case err := <-r.done:
+		r.done <- err
+		if _, statErr := os.Stat(r.path); statErr != nil {
+			return "", fmt.Errorf("recordVideo: %v: %s", err, strings.TrimSpace(r.stderr.String()))
+		}
+		return r.path, nil
+	case <-ctx.Done():
+		_ = r.cmd.Process.Kill()
+		return "", ctx.Err()
+	}
+}
+
+func (r *VideoRecording) interrupt() {
+	_ = r.cmd.Process.Signal(os.Interrupt)
+}
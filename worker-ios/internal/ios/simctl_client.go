@@ -0,0 +1,118 @@
+package ios
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SimctlClient runs `xcrun simctl` commands scoped to a single simulator.
+type SimctlClient struct {
+	xcrunPath string
+	udid      string
+}
+
+func NewSimctlClient(xcrunPath, udid string) *SimctlClient {
+	return &SimctlClient{xcrunPath: xcrunPath, udid: udid}
+}
+
+func (c *SimctlClient) LaunchApp(ctx context.Context, bundleID string, stopExisting bool) error {
+	args := []string{"launch"}
+	if stopExisting {
+		args = append(args, "--terminate-running-process")
+	}
+	_, err := c.run(ctx, append(args, c.udid, bundleID)...)
+	return err
+}
+
+func (c *SimctlClient) TerminateApp(ctx context.Context, bundleID string) error {
+	_, err := c.run(ctx, "terminate", c.udid, bundleID)
+	return err
+}
+
+func (c *SimctlClient) InstallApp(ctx context.Context, appPath string) error {
+	_, err := c.run(ctx, "install", c.udid, appPath)
+	return err
+}
+
+func (c *SimctlClient) UninstallApp(ctx context.Context, bundleID string) error {
+	_, err := c.run(ctx, "uninstall", c.udid, bundleID)
+	return err
+}
+
+func (c *SimctlClient) OpenURL(ctx context.Context, url string) error {
+	_, err := c.run(ctx, "openurl", c.udid, url)
+	return err
+}
+
+// SetLocation pins the simulated location. simctl has no altitude input, so
+// altitude is not applied on iOS.
+func (c *SimctlClient) SetLocation(ctx context.Context, lat, lon float64) error {
+	coord := strconv.FormatFloat(lat, 'f', -1, 64) + "," + strconv.FormatFloat(lon, 'f', -1, 64)
+	_, err := c.run(ctx, "location", c.udid, "set", coord)
+	return err
+}
+
+// Privacy runs `simctl privacy` with action grant, revoke or reset for a TCC
+// service such as camera, photos or location.
+func (c *SimctlClient) Privacy(ctx context.Context, action, service, bundleID string) error {
+	_, err := c.run(ctx, "privacy", c.udid, action, service, bundleID)
+	return err
+}
+
+// Pbpaste reads the simulator pasteboard from the host side, which unlike WDA
+// does not trigger the iOS paste permission banner.
+func (c *SimctlClient) Pbpaste(ctx context.Context) (string, error) {
+	return c.run(ctx, "pbpaste", c.udid)
+}
+
+func (c *SimctlClient) Pbcopy(ctx context.Context, text string) error {
+	cmd := exec.CommandContext(ctx, c.xcrunPath, "simctl", "pbcopy", c.udid)
+	cmd.Stdin = strings.NewReader(text)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("simctl pbcopy: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// ClearAppData terminates the app and empties its data container. simctl has no
+// direct equivalent of `pm clear`, but simulator containers live on the worker
+// host so their contents can be removed in place.
+func (c *SimctlClient) ClearAppData(ctx context.Context, bundleID string) error {
+	// terminate fails when the app is not running, which is fine here.
+	_, _ = c.run(ctx, "terminate", c.udid, bundleID)
+
+	out, err := c.run(ctx, "get_app_container", c.udid, bundleID, "data")
+	if err != nil {
+		return err
+	}
+	container := strings.TrimSpace(out)
+	entries, err := os.ReadDir(container)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(container, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *SimctlClient) run(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, c.xcrunPath, append([]string{"simctl"}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return stdout.String(), fmt.Errorf("simctl %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
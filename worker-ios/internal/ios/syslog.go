@@ -0,0 +1,105 @@
+package ios
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// LogEntry is one event from `log stream --style ndjson`. Type is the unified
+// logging message type: Debug, Info, Default, Error or Fault.
+type LogEntry struct {
+	Time      time.Time
+	Type      string
+	Subsystem string
+	Category  string
+	Process   string
+	PID       int32
+	TID       int64
+	Message   string
+}
+
+type ndjsonEvent struct {
+	Timestamp        string `json:"timestamp"`
+	MessageType      string `json:"messageType"`
+	EventType        string `json:"eventType"`
+	Subsystem        string `json:"subsystem"`
+	Category         string `json:"category"`
+	ProcessImagePath string `json:"processImagePath"`
+	ProcessID        int32  `json:"processID"`
+	ThreadID         int64  `json:"threadID"`
+	EventMessage     string `json:"eventMessage"`
+}
+
+const logTimestampLayout = "2006-01-02 15:04:05.000000-0700"
+
+// LogStream follows the simulator's unified log through `simctl spawn log
+// stream` and sends parsed entries until ctx ends. entries is closed when
+// LogStream returns.
+func (c *SimctlClient) LogStream(ctx context.Context, predicate string, entries chan<- LogEntry) error {
+	defer close(entries)
+
+	args := []string{"simctl", "spawn", c.udid, "log", "stream", "--style", "ndjson", "--level", "debug"}
+	if predicate != "" {
+		args = append(args, "--predicate", predicate)
+	}
+	cmd := exec.CommandContext(ctx, c.xcrunPath, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var evt ndjsonEvent
+		if err := json.Unmarshal(scanner.Bytes(), &evt); err != nil || evt.EventType != "logEvent" {
+			continue
+		}
+		ts, _ := time.Parse(logTimestampLayout, evt.Timestamp)
+		entry := LogEntry{
+			Time:      ts.UTC(),
+			Type:      evt.MessageType,
+			Subsystem: evt.Subsystem,
+			Category:  evt.Category,
+			Process:   processName(evt.ProcessImagePath),
+			PID:       evt.ProcessID,
+			TID:       evt.ThreadID,
+			Message:   evt.EventMessage,
+		}
+		select {
+		case entries <- entry:
+		case <-ctx.Done():
+			_ = cmd.Wait()
+			return ctx.Err()
+		}
+	}
+	if err := cmd.Wait(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("log stream exited: %w", err)
+	}
+	return ctx.Err()
+}
+
+// AppContainer returns the path of the installed .app bundle, which prefixes the
+// image path of every process the app runs.
+func (c *SimctlClient) AppContainer(ctx context.Context, bundleID string) (string, error) {
+	out, err := c.run(ctx, "get_app_container", c.udid, bundleID, "app")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func processName(imagePath string) string {
+	if i := strings.LastIndex(imagePath, "/"); i >= 0 {
+		return imagePath[i+1:]
+	}
+	return imagePath
+}
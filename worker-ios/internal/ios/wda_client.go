@@ -6,20 +6,33 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
 	"io"
 	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/fast-mobile-mcp/shared/snapshot"
 )
 
+// webElementKey is the W3C element identifier key WDA returns alongside the
+// legacy ELEMENT key.
+const webElementKey = "element-6066-11e4-a52e-4f735466cecf"
+
 type WDAClient struct {
 	baseURL string
 	http    *http.Client
+
+	scaleMu sync.Mutex
+	scale   float64
 }
 
 type ActiveApp struct {
@@ -136,11 +149,82 @@ func (c *WDAClient) Tap(ctx context.Context, x, y int32, tapCount int32) error {
 	return c.postJSON(ctx, "/wda/tap/0", body)
 }
 
-func (c *WDAClient) Type(ctx context.Context, text string) error {
+// deleteKey is XCUIKeyboardKeyDelete as understood by /wda/keys.
+const deleteKey = "\b"
+
+func (c *WDAClient) Type(ctx context.Context, text string, clear bool) error {
+	if clear {
+		if err := c.ClearFocused(ctx); err != nil {
+			return err
+		}
+	}
 	body := map[string]any{"value": strings.Split(text, "")}
 	return c.postJSON(ctx, "/wda/keys", body)
 }
 
+// ClearFocused empties the element that currently has keyboard focus. It prefers
+// WDA's element clear and falls back to moving the caret to the end of the field
+// and deleting its value key by key when the element rejects it (some custom
+// inputs do not support clear). The field must read empty, or show only its
+// placeholder, afterwards.
+func (c *WDAClient) ClearFocused(ctx context.Context) error {
+	id, err := c.activeElement(ctx)
+	if err != nil {
+		return fmt.Errorf("no focused element to clear: %w", err)
+	}
+	if err := c.postJSON(ctx, "/element/"+id+"/clear", map[string]any{}); err == nil {
+		return nil
+	}
+
+	value, err := c.elementValue(ctx, id)
+	if err != nil {
+		return err
+	}
+	placeholder, _ := c.elementAttribute(ctx, id, "placeholderValue")
+	if value == "" || value == placeholder {
+		return nil
+	}
+
+	// Tapping just inside the trailing edge puts the caret after the last
+	// character, so the deletes remove the whole value wherever the caret was.
+	rect, err := c.ElementRect(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := c.Tap(ctx, rect.Right-2, (rect.Top+rect.Bottom)/2, 1); err != nil {
+		return err
+	}
+	count := utf8.RuneCountInString(value)
+	if err := c.postJSON(ctx, "/wda/keys", map[string]any{"value": strings.Split(strings.Repeat(deleteKey, count), "")}); err != nil {
+		return err
+	}
+
+	left, err := c.elementValue(ctx, id)
+	if err != nil {
+		return err
+	}
+	if left != "" && left != placeholder {
+		return fmt.Errorf("field still reads %q after clearing", left)
+	}
+	return nil
+}
+
+// ReplaceText clears the focused element, sets its value to text and returns
+// the value the element reports afterwards so callers can verify the result.
+func (c *WDAClient) ReplaceText(ctx context.Context, text string) (string, error) {
+	if err := c.ClearFocused(ctx); err != nil {
+		return "", err
+	}
+	id, err := c.activeElement(ctx)
+	if err != nil {
+		return "", err
+	}
+	if err := c.postJSON(ctx, "/element/"+id+"/value", map[string]any{"value": strings.Split(text, "")}); err != nil {
+		return "", err
+	}
+	return c.elementValue(ctx, id)
+}
+
 func (c *WDAClient) Swipe(ctx context.Context, sx, sy, ex, ey, durationMS int32) error {
 	body := map[string]any{
 		"fromX":    sx,
@@ -175,8 +259,250 @@ func (c *WDAClient) Screenshot(ctx context.Context) ([]byte, int32, int32, error
 		return nil, 0, 0, err
 	}
 
-	// Dimensions can be derived if needed from decoded image; left as optional for latency.
-	return data, 0, 0, nil
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return data, 0, 0, nil
+	}
+
+	return data, int32(cfg.Width), int32(cfg.Height), nil
+}
+
+// ScreenScale returns the screen's pixels per point, e.g. 3 on current iPhones.
+// Screenshots are in pixels while element frames and taps are in points. The
+// scale is fixed for a device, so it is fetched once.
+func (c *WDAClient) ScreenScale(ctx context.Context) (float64, error) {
+	c.scaleMu.Lock()
+	defer c.scaleMu.Unlock()
+	if c.scale > 0 {
+		return c.scale, nil
+	}
+	var payload struct {
+		Value struct {
+			Scale float64 `json:"scale"`
+		} `json:"value"`
+	}
+	if err := c.getJSON(ctx, "/wda/screen", &payload); err != nil {
+		return 0, err
+	}
+	if payload.Value.Scale <= 0 {
+		return 0, fmt.Errorf("wda reported screen scale %v", payload.Value.Scale)
+	}
+	c.scale = payload.Value.Scale
+	return c.scale, nil
+}
+
+func (c *WDAClient) GetPasteboard(ctx context.Context) (string, error) {
+	raw, err := c.postJSONValue(ctx, "/wda/getPasteboard", map[string]any{"contentType": "plaintext"})
+	if err != nil {
+		return "", err
+	}
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (c *WDAClient) SetPasteboard(ctx context.Context, text string) error {
+	body := map[string]any{
+		"content":     base64.StdEncoding.EncodeToString([]byte(text)),
+		"contentType": "plaintext",
+	}
+	return c.postJSON(ctx, "/wda/setPasteboard", body)
+}
+
+// ErrNoAlert is returned by the alert methods when no alert is on screen.
+var ErrNoAlert = errors.New("no alert present")
+
+func (c *WDAClient) AlertText(ctx context.Context) (string, error) {
+	var payload struct {
+		Value string `json:"value"`
+	}
+	if err := c.getJSON(ctx, "/alert/text", &payload); err != nil {
+		return "", alertError(err)
+	}
+	return payload.Value, nil
+}
+
+func (c *WDAClient) AlertButtons(ctx context.Context) ([]string, error) {
+	var payload struct {
+		Value []string `json:"value"`
+	}
+	if err := c.getJSON(ctx, "/wda/alert/buttons", &payload); err != nil {
+		return nil, alertError(err)
+	}
+	return payload.Value, nil
+}
+
+// AcceptAlert taps the alert's default button, or the button labelled label
+// when one is given.
+func (c *WDAClient) AcceptAlert(ctx context.Context, label string) error {
+	return alertError(c.postJSON(ctx, "/alert/accept", alertBody(label)))
+}
+
+// DismissAlert taps the alert's cancel button, or the button labelled label
+// when one is given.
+func (c *WDAClient) DismissAlert(ctx context.Context, label string) error {
+	return alertError(c.postJSON(ctx, "/alert/dismiss", alertBody(label)))
+}
+
+func alertBody(label string) map[string]any {
+	if label == "" {
+		return map[string]any{}
+	}
+	return map[string]any{"name": label}
+}
+
+// alertError maps WDA's "no such alert" response onto ErrNoAlert.
+func alertError(err error) error {
+	if err != nil && strings.Contains(err.Error(), "no such alert") {
+		return ErrNoAlert
+	}
+	return err
+}
+
+func (c *WDAClient) DismissKeyboard(ctx context.Context) error {
+	return c.postJSON(ctx, "/wda/keyboard/dismiss", map[string]any{})
+}
+
+// Orientation returns the WDA orientation name, e.g. PORTRAIT or
+// UIA_DEVICE_ORIENTATION_LANDSCAPERIGHT.
+func (c *WDAClient) Orientation(ctx context.Context) (string, error) {
+	var payload struct {
+		Value string `json:"value"`
+	}
+	if err := c.getJSON(ctx, "/orientation", &payload); err != nil {
+		return "", err
+	}
+	return payload.Value, nil
+}
+
+func (c *WDAClient) SetOrientation(ctx context.Context, orientation string) error {
+	return c.postJSON(ctx, "/orientation", map[string]any{"orientation": orientation})
+}
+
+// WindowSize returns the screen size in points, the unit WDA uses for element
+// frames and gestures.
+func (c *WDAClient) WindowSize(ctx context.Context) (int32, int32, error) {
+	var payload struct {
+		Value struct {
+			Width  float64 `json:"width"`
+			Height float64 `json:"height"`
+		} `json:"value"`
+	}
+	if err := c.getJSON(ctx, "/window/size", &payload); err != nil {
+		return 0, 0, err
+	}
+	return int32(payload.Value.Width), int32(payload.Value.Height), nil
+}
+
+// ActiveElement returns the id of the element that has keyboard focus.
+func (c *WDAClient) ActiveElement(ctx context.Context) (string, error) {
+	return c.activeElement(ctx)
+}
+
+// ElementRect returns an element's frame in points.
+func (c *WDAClient) ElementRect(ctx context.Context, id string) (snapshot.Bounds, error) {
+	var payload struct {
+		Value struct {
+			X      float64 `json:"x"`
+			Y      float64 `json:"y"`
+			Width  float64 `json:"width"`
+			Height float64 `json:"height"`
+		} `json:"value"`
+	}
+	if err := c.getJSON(ctx, "/element/"+id+"/rect", &payload); err != nil {
+		return snapshot.Bounds{}, err
+	}
+	r := payload.Value
+	return snapshot.Bounds{
+		Left:   int32(r.X),
+		Top:    int32(r.Y),
+		Right:  int32(r.X + r.Width),
+		Bottom: int32(r.Y + r.Height),
+	}, nil
+}
+
+func (c *WDAClient) activeElement(ctx context.Context) (string, error) {
+	var payload struct {
+		Value map[string]any `json:"value"`
+	}
+	if err := c.getJSON(ctx, "/element/active", &payload); err != nil {
+		return "", err
+	}
+	id := stringValue(payload.Value, "ELEMENT")
+	if id == "" {
+		id = stringValue(payload.Value, webElementKey)
+	}
+	if id == "" {
+		return "", fmt.Errorf("wda returned no active element")
+	}
+	return id, nil
+}
+
+func (c *WDAClient) elementValue(ctx context.Context, id string) (string, error) {
+	return c.elementAttribute(ctx, id, "value")
+}
+
+func (c *WDAClient) elementAttribute(ctx context.Context, id, name string) (string, error) {
+	var payload struct {
+		Value any `json:"value"`
+	}
+	if err := c.getJSON(ctx, "/element/"+id+"/attribute/"+name, &payload); err != nil {
+		return "", err
+	}
+	value, _ := payload.Value.(string)
+	return value, nil
+}
+
+func (c *WDAClient) getJSON(ctx context.Context, route string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+route, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		bodyRaw, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("wda request failed status=%d body=%s", resp.StatusCode, string(bodyRaw))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// postJSONValue posts body and returns the raw "value" field of the WDA response.
+func (c *WDAClient) postJSONValue(ctx context.Context, route string, body any) (json.RawMessage, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+route, bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		bodyRaw, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("wda request failed status=%d body=%s", resp.StatusCode, string(bodyRaw))
+	}
+	var payload struct {
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	return payload.Value, nil
 }
 
 func (c *WDAClient) postJSON(ctx context.Context, route string, body any) error {
@@ -220,6 +546,7 @@ func walkSourceTree(node sourceNode, parentRef string, index int32, counter *int
 		Visible:     attrBoolDefault(node.Attrs, "visible", true),
 		Selected:    attrBool(node.Attrs, "selected"),
 		Checked:     attrBool(node.Attrs, "value"),
+		Focused:     attrBool(node.Attrs, "focused"),
 		Bounds:      parseRect(attrValue(node.Attrs, "rect")),
 	}
 	*out = append(*out, item)
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+	"github.com/fast-mobile-mcp/worker-ios/internal/ios"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type alertState struct {
+	text    string
+	buttons []string
+}
+
+// GetAlert reads the alert through WDA's alert endpoints, which see system
+// alerts that are often missing or unlabelled in /source.
+func (s *MobileService) GetAlert(ctx context.Context, req *mobilev1.GetAlertRequest) (*mobilev1.GetAlertResponse, error) {
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	out, err := runtime.Executor.Submit(ctx, executor.LaneQuery, func(runCtx context.Context) (any, error) {
+		text, textErr := runtime.WDA.AlertText(runCtx)
+		if textErr != nil {
+			return nil, textErr
+		}
+		buttons, buttonsErr := runtime.WDA.AlertButtons(runCtx)
+		if buttonsErr != nil {
+			return nil, buttonsErr
+		}
+		return alertState{text: text, buttons: buttons}, nil
+	})
+	resp := &mobilev1.GetAlertResponse{
+		DeviceId:         req.DeviceId,
+		ObservedAtUnixMs: time.Now().UTC().UnixMilli(),
+	}
+	if errors.Is(err, ios.ErrNoAlert) {
+		return resp, nil
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	alert := out.(alertState)
+	resp.Present = true
+	resp.Text = alert.text
+	resp.Buttons = alert.buttons
+	return resp, nil
+}
+
+func (s *MobileService) AcceptAlert(ctx context.Context, req *mobilev1.AlertActionRequest) (*mobilev1.ActionResponse, error) {
+	return s.alertAction(ctx, req, func(runCtx context.Context, wda *ios.WDAClient) error {
+		return wda.AcceptAlert(runCtx, req.ButtonLabel)
+	})
+}
+
+func (s *MobileService) DismissAlert(ctx context.Context, req *mobilev1.AlertActionRequest) (*mobilev1.ActionResponse, error) {
+	return s.alertAction(ctx, req, func(runCtx context.Context, wda *ios.WDAClient) error {
+		return wda.DismissAlert(runCtx, req.ButtonLabel)
+	})
+}
+
+func (s *MobileService) alertAction(ctx context.Context, req *mobilev1.AlertActionRequest, run func(context.Context, *ios.WDAClient) error) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	_, err = runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		return nil, run(runCtx, runtime.WDA)
+	})
+	if errors.Is(err, ios.ErrNoAlert) {
+		return actionFailed(req.DeviceId, start, "NO_ALERT", err), nil
+	}
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "ALERT_ACTION_FAILED", err), nil
+	}
+	resp := actionOK(req.DeviceId, start)
+	if req.ButtonLabel != "" {
+		resp.Metadata["button"] = req.ButtonLabel
+	}
+	return resp, nil
+}
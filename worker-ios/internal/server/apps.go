@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+	"github.com/fast-mobile-mcp/worker-ios/internal/device"
+	"github.com/fast-mobile-mcp/worker-ios/internal/ios"
+)
+
+var (
+	errMissingAppID        = errors.New("app_id is required")
+	errActivityUnsupported = errors.New("activity is Android-only; iOS apps launch at their default scene")
+)
+
+func (s *MobileService) LaunchApp(ctx context.Context, req *mobilev1.LaunchAppRequest) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	if req.AppId == "" {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", errMissingAppID), nil
+	}
+	if req.Activity != "" {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", errActivityUnsupported), nil
+	}
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.timeoutContext(ctx, req.Options, s.cfg.AppTimeout)
+	defer cancel()
+
+	_, err = runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		return nil, runtime.Simctl.LaunchApp(runCtx, req.AppId, req.StopExisting)
+	})
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "LAUNCH_FAILED", err), nil
+	}
+	return actionOK(req.DeviceId, start), nil
+}
+
+func (s *MobileService) TerminateApp(ctx context.Context, req *mobilev1.AppRequest) (*mobilev1.ActionResponse, error) {
+	return s.appAction(ctx, req, "TERMINATE_FAILED", func(runCtx context.Context, rt *device.Runtime) error {
+		return rt.Simctl.TerminateApp(runCtx, req.AppId)
+	})
+}
+
+func (s *MobileService) UninstallApp(ctx context.Context, req *mobilev1.AppRequest) (*mobilev1.ActionResponse, error) {
+	return s.appAction(ctx, req, "UNINSTALL_FAILED", func(runCtx context.Context, rt *device.Runtime) error {
+		return rt.Simctl.UninstallApp(runCtx, req.AppId)
+	})
+}
+
+func (s *MobileService) ClearAppData(ctx context.Context, req *mobilev1.AppRequest) (*mobilev1.ActionResponse, error) {
+	return s.appAction(ctx, req, "CLEAR_DATA_FAILED", func(runCtx context.Context, rt *device.Runtime) error {
+		return rt.Simctl.ClearAppData(runCtx, req.AppId)
+	})
+}
+
+func (s *MobileService) InstallApp(ctx context.Context, req *mobilev1.InstallAppRequest) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	if req.AppPath == "" {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", errors.New("app_path is required")), nil
+	}
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.timeoutContext(ctx, req.Options, s.cfg.InstallTimeout)
+	defer cancel()
+
+	_, err = runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		return nil, runtime.Simctl.InstallApp(runCtx, req.AppPath)
+	})
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "INSTALL_FAILED", err), nil
+	}
+	return actionOK(req.DeviceId, start), nil
+}
+
+// appAction runs a bundle-scoped lifecycle command on the device executor and
+// reports it as an ActionResponse.
+func (s *MobileService) appAction(ctx context.Context, req *mobilev1.AppRequest, failCode string, run func(context.Context, *device.Runtime) error) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	if req.AppId == "" {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", errMissingAppID), nil
+	}
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.timeoutContext(ctx, req.Options, s.cfg.AppTimeout)
+	defer cancel()
+
+	_, err = runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		return nil, run(runCtx, runtime)
+	})
+	if err != nil {
+		return actionFailed(req.DeviceId, start, failCode, err), nil
+	}
+	return actionOK(req.DeviceId, start), nil
+}
+
+const foregroundPollInterval = 200 * time.Millisecond
+
+func (s *MobileService) OpenURL(ctx context.Context, req *mobilev1.OpenURLRequest) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	if req.Url == "" {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", errors.New("url is required")), nil
+	}
+	if req.WaitForApp && req.AppId == "" {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", errors.New("wait_for_app requires app_id")), nil
+	}
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.timeoutContext(ctx, req.Options, s.cfg.AppTimeout)
+	defer cancel()
+
+	_, err = runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		return nil, runtime.Simctl.OpenURL(runCtx, req.Url)
+	})
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "OPEN_URL_FAILED", err), nil
+	}
+	if !req.WaitForApp {
+		return actionOK(req.DeviceId, start), nil
+	}
+
+	app, err := waitForForeground(ctx, runtime, req.AppId)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return actionFailed(req.DeviceId, start, "APP_NOT_FOREGROUND_TIMEOUT", err), nil
+	}
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "OPEN_URL_FAILED", err), nil
+	}
+	resp := actionOK(req.DeviceId, start)
+	resp.Metadata["foreground_app"] = app
+	return resp, nil
+}
+
+// waitForForeground polls the active app until appID is in front or ctx ends.
+// Each poll is its own short query job, so waiting for a slow app does not hold
+// the device against other callers.
+func waitForForeground(ctx context.Context, runtime *device.Runtime, appID string) (string, error) {
+	for {
+		out, err := runtime.Executor.Submit(ctx, executor.LaneQuery, func(runCtx context.Context) (any, error) {
+			return runtime.WDA.GetActiveApp(runCtx)
+		})
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if err == nil {
+			if app := out.(ios.ActiveApp); app.BundleID == appID {
+				return app.BundleID, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(foregroundPollInterval):
+		}
+	}
+}
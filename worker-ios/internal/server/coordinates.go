@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+	"github.com/fast-mobile-mcp/worker-ios/internal/device"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// coordinateModelMaxAge bounds how long a measurement is trusted for converting
+// coordinates; a rotation in between would otherwise map taps wrongly.
+const coordinateModelMaxAge = time.Second
+
+// WDA taps are in points.
+const nativeSpace = mobilev1.CoordinateSpace_COORDINATE_SPACE_POINTS
+
+// Direction swipes without a distance cover this share of the screen, and no
+// swipe covers more than swipeMaxShare, so both ends stay on screen.
+const (
+	swipeDefaultShare = 0.4
+	swipeMaxShare     = 0.8
+)
+
+var errMissingScreenshotScale = errors.New("screenshot_scale is required for screenshot coordinates")
+
+func (s *MobileService) GetCoordinateModel(ctx context.Context, req *mobilev1.GetCoordinateModelRequest) (*mobilev1.CoordinateModel, error) {
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	out, err := runtime.Executor.Submit(ctx, executor.LaneQuery, func(runCtx context.Context) (any, error) {
+		return measureCoordinates(runCtx, runtime)
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	c := out.(device.Coordinates)
+	return &mobilev1.CoordinateModel{
+		DeviceId:         req.DeviceId,
+		LogicalWidth:     c.LogicalWidth,
+		LogicalHeight:    c.LogicalHeight,
+		PixelWidth:       c.PixelWidth,
+		PixelHeight:      c.PixelHeight,
+		Scale:            float32(c.Scale),
+		Orientation:      orientationFromBackend(c.Orientation),
+		NativeSpace:      nativeSpace,
+		MeasuredAtUnixMs: c.MeasuredAt.UnixMilli(),
+	}, nil
+}
+
+// measureCoordinates reads the window size, which WDA already reports for the
+// current rotation, and caches the result on the runtime. It must run inside an
+// executor job.
+func measureCoordinates(ctx context.Context, runtime *device.Runtime) (device.Coordinates, error) {
+	width, height, err := runtime.WDA.WindowSize(ctx)
+	if err != nil {
+		return device.Coordinates{}, err
+	}
+	scale, err := runtime.WDA.ScreenScale(ctx)
+	if err != nil {
+		return device.Coordinates{}, err
+	}
+	orientation, err := runtime.WDA.Orientation(ctx)
+	if err != nil {
+		return device.Coordinates{}, err
+	}
+	c := device.Coordinates{
+		LogicalWidth:  width,
+		LogicalHeight: height,
+		PixelWidth:    int32(math.Round(float64(width) * scale)),
+		PixelHeight:   int32(math.Round(float64(height) * scale)),
+		Scale:         scale,
+		Orientation:   orientation,
+		MeasuredAt:    time.Now().UTC(),
+	}
+	runtime.SetCoordinates(c)
+	return c, nil
+}
+
+// validateCoordinates rejects coordinates that cannot be converted, before any
+// work is queued on the device.
+func validateCoordinates(space mobilev1.CoordinateSpace, screenshotScale float32, coords ...*mobilev1.Coordinates) error {
+	switch space {
+	case mobilev1.CoordinateSpace_COORDINATE_SPACE_SCREENSHOT:
+		if screenshotScale <= 0 || !finite(screenshotScale) {
+			return errMissingScreenshotScale
+		}
+	case mobilev1.CoordinateSpace_COORDINATE_SPACE_NORMALIZED:
+		for _, c := range coords {
+			if c == nil {
+				continue
+			}
+			if !finite(c.NormalizedX) || !finite(c.NormalizedY) {
+				return fmt.Errorf("normalized coordinates must be finite, got (%v, %v)", c.NormalizedX, c.NormalizedY)
+			}
+			if c.NormalizedX < 0 || c.NormalizedX > 1 || c.NormalizedY < 0 || c.NormalizedY > 1 {
+				return fmt.Errorf("normalized coordinates must be within [0, 1], got (%v, %v)", c.NormalizedX, c.NormalizedY)
+			}
+		}
+	}
+	return nil
+}
+
+func finite(v float32) bool {
+	return !math.IsNaN(float64(v)) && !math.IsInf(float64(v), 0)
+}
+
+// nativePoint converts coords given in space into points. It must run inside an
+// executor job, since it may need to measure the screen.
+func nativePoint(ctx context.Context, runtime *device.Runtime, space mobilev1.CoordinateSpace, screenshotScale float32, coords *mobilev1.Coordinates) (point, error) {
+	switch space {
+	case mobilev1.CoordinateSpace_COORDINATE_SPACE_UNSPECIFIED, nativeSpace:
+		return point{x: coords.X, y: coords.Y}, nil
+	case mobilev1.CoordinateSpace_COORDINATE_SPACE_SCREENSHOT:
+		return scalePoint(float64(coords.X), float64(coords.Y), 1/float64(screenshotScale)), nil
+	}
+
+	c, err := currentCoordinates(ctx, runtime)
+	if err != nil {
+		return point{}, err
+	}
+	switch space {
+	case mobilev1.CoordinateSpace_COORDINATE_SPACE_PIXELS:
+		return scalePoint(float64(coords.X), float64(coords.Y), 1/c.Scale), nil
+	case mobilev1.CoordinateSpace_COORDINATE_SPACE_NORMALIZED:
+		return point{
+			x: int32(math.Round(float64(coords.NormalizedX) * float64(c.LogicalWidth))),
+			y: int32(math.Round(float64(coords.NormalizedY) * float64(c.LogicalHeight))),
+		}, nil
+	}
+	return point{}, fmt.Errorf("unsupported coordinate space %v", space)
+}
+
+func scalePoint(x, y, factor float64) point {
+	return point{x: int32(math.Round(x * factor)), y: int32(math.Round(y * factor))}
+}
+
+// currentCoordinates returns the cached coordinate model, measuring it again
+// when it is stale. It must run inside an executor job.
+func currentCoordinates(ctx context.Context, runtime *device.Runtime) (device.Coordinates, error) {
+	if c, ok := runtime.CachedCoordinates(coordinateModelMaxAge); ok {
+		return c, nil
+	}
+	return measureCoordinates(ctx, runtime)
+}
+
+// directionSwipe places a swipe of distance native units along direction,
+// centered on the screen. A distance of zero or less covers
+// swipeDefaultShare of the screen along that axis.
+func directionSwipe(c device.Coordinates, direction mobilev1.Direction, distance int32) (point, point) {
+	width, height := c.LogicalWidth, c.LogicalHeight
+	axis := height
+	if direction == mobilev1.Direction_DIRECTION_LEFT || direction == mobilev1.Direction_DIRECTION_RIGHT {
+		axis = width
+	}
+	if distance <= 0 {
+		distance = int32(math.Round(float64(axis) * swipeDefaultShare))
+	}
+	distance = min(distance, int32(math.Round(float64(axis)*swipeMaxShare)))
+
+	center := point{x: width / 2, y: height / 2}
+	from, to := center, center
+	switch direction {
+	case mobilev1.Direction_DIRECTION_DOWN:
+		from.y, to.y = center.y-distance/2, center.y+distance/2
+	case mobilev1.Direction_DIRECTION_LEFT:
+		from.x, to.x = center.x+distance/2, center.x-distance/2
+	case mobilev1.Direction_DIRECTION_RIGHT:
+		from.x, to.x = center.x-distance/2, center.x+distance/2
+	default:
+		from.y, to.y = center.y+distance/2, center.y-distance/2
+	}
+	return from, to
+}
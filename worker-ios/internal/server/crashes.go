@@ -0,0 +1,216 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/worker-ios/internal/ios"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// crashGrace widens an action's window on both sides to absorb the skew
+// between the host and device clocks. Crashes logged after an action returned
+// are claimed by the device's next action, as long as they happened within
+// crashLateLimit of the earlier action ending.
+const (
+	crashGrace     = 500 * time.Millisecond
+	crashLateLimit = 10 * time.Second
+)
+
+// GetCrashes returns, and clears, the crashes and ANRs the device's crash
+// watcher has seen that no action claimed.
+func (s *MobileService) GetCrashes(ctx context.Context, req *mobilev1.GetCrashesRequest) (*mobilev1.GetCrashesResponse, error) {
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	out := &mobilev1.GetCrashesResponse{DeviceId: req.DeviceId}
+	for _, crash := range runtime.Crashes.Pending() {
+		out.Crashes = append(out.Crashes, &mobilev1.CrashReport{
+			Kind:    crash.Kind,
+			Process: crash.Process,
+			AppId:   crash.App,
+			UnixMs:  crash.Time.UnixMilli(),
+			Excerpt: crash.Excerpt,
+		})
+	}
+	return out, nil
+}
+
+// AttachCrashes is a unary interceptor that marks an ActionResponse as
+// APP_CRASHED when the device's crash watcher saw the action's target app
+// crash or stop responding while the action ran, so the failure surfaces on
+// the call that caused it rather than as a missing element on the next one.
+// An app usually dies a moment after the tap that broke it returned, so a
+// crash of the previous action's app between that action and this one is
+// attached here too, marked crash_during_previous_action. Crashes outside
+// those windows or in other apps are left for GetCrashes.
+func (s *MobileService) AttachCrashes(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	started := time.Now()
+	resp, err := handler(ctx, req)
+	ended := time.Now()
+	if err != nil {
+		return resp, err
+	}
+	if active, ok := resp.(*mobilev1.GetActiveAppResponse); ok {
+		s.apps.observe(active.DeviceId, active.BundleId)
+	}
+	action, ok := resp.(*mobilev1.ActionResponse)
+	if !ok || action.DeviceId == "" {
+		return resp, err
+	}
+	runtime, ok := s.registry.Lookup(action.DeviceId)
+	if !ok {
+		return resp, err
+	}
+	if action.Status == mobilev1.ActionStatus_ACTION_STATUS_OK {
+		switch r := req.(type) {
+		case *mobilev1.LaunchAppRequest:
+			s.apps.observe(r.DeviceId, r.AppId)
+		case *mobilev1.OpenURLRequest:
+			s.apps.observe(r.DeviceId, r.AppId)
+		}
+	}
+	// ReportCrash writes reports some time after the crash; read any that
+	// landed since the last poll.
+	runtime.Crashes.Sync()
+	window := actionWindow{started: started, ended: ended, app: s.targetApp(action.DeviceId, req)}
+	crashes, late := claimCrashes(runtime.Crashes, window, s.actions.swap(action.DeviceId, window))
+	if len(crashes) == 0 {
+		return resp, err
+	}
+
+	if action.Metadata == nil {
+		action.Metadata = map[string]string{}
+	}
+	// The most recent crash is reported; earlier ones are usually its restarts.
+	crash := crashes[len(crashes)-1]
+	if action.ErrorCode != "" {
+		action.Metadata["original_error_code"] = action.ErrorCode
+		action.Metadata["original_error_message"] = action.ErrorMessage
+	}
+	action.Status = mobilev1.ActionStatus_ACTION_STATUS_FAILED
+	action.ErrorCode = "APP_CRASHED"
+	action.ErrorMessage = fmt.Sprintf("%s crashed", crash.Process)
+	if crash.Kind == "anr" {
+		action.ErrorMessage = fmt.Sprintf("%s is not responding", crash.Process)
+	}
+	action.Metadata["crash_kind"] = crash.Kind
+	action.Metadata["crash_process"] = crash.Process
+	action.Metadata["crash_app_id"] = crash.App
+	action.Metadata["crash_unix_ms"] = strconv.FormatInt(crash.Time.UnixMilli(), 10)
+	action.Metadata["crash_stack"] = crash.Excerpt
+	action.Metadata["crash_count"] = strconv.Itoa(len(crashes))
+	if late {
+		action.Metadata["crash_during_previous_action"] = "true"
+	}
+	return action, nil
+}
+
+// crashTaker is the part of the crash watcher AttachCrashes needs.
+type crashTaker interface {
+	Take(since, until time.Time, app string) []ios.Crash
+}
+
+// actionWindow is when an action ran and the app it was aimed at.
+type actionWindow struct {
+	started time.Time
+	ended   time.Time
+	app     string
+}
+
+// claimCrashes takes the crashes that belong to action: those of its app in
+// its own window or, when there are none, those of the previous action's app
+// logged after that action started and before this one did. late reports the
+// second case.
+func claimCrashes(w crashTaker, action actionWindow, previous *actionWindow) (crashes []ios.Crash, late bool) {
+	crashes = w.Take(action.started.Add(-crashGrace), action.ended.Add(crashGrace), action.app)
+	if len(crashes) > 0 || previous == nil {
+		return crashes, false
+	}
+	until := action.started
+	if limit := previous.ended.Add(crashLateLimit); limit.Before(until) {
+		until = limit
+	}
+	crashes = w.Take(previous.started.Add(-crashGrace), until, previous.app)
+	return crashes, len(crashes) > 0
+}
+
+// actionLog remembers each device's last action.
+type actionLog struct {
+	mu   sync.Mutex
+	last map[string]actionWindow
+}
+
+func newActionLog() *actionLog {
+	return &actionLog{last: make(map[string]actionWindow)}
+}
+
+// swap records window as the device's last action and returns the one before
+// it, if any.
+func (l *actionLog) swap(deviceID string, window actionWindow) *actionWindow {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	previous, ok := l.last[deviceID]
+	l.last[deviceID] = window
+	if !ok {
+		return nil
+	}
+	return &previous
+}
+
+// targetApp names the app an action was aimed at: the request's app_id when it
+// has one, otherwise whichever of the latest snapshot's package and the last
+// app seen launched or in the foreground is newer. An empty result means the
+// app is unknown and any crash in the window is attributed to the action.
+func (s *MobileService) targetApp(deviceID string, req any) string {
+	if scoped, ok := req.(interface{ GetAppId() string }); ok && scoped.GetAppId() != "" {
+		return scoped.GetAppId()
+	}
+	app, seenAt := s.apps.last(deviceID)
+	if snap, ok := s.store.Latest(deviceID); ok && snap.CreatedAt.After(seenAt) {
+		for _, node := range snap.Nodes {
+			if node.PackageName != "" {
+				return node.PackageName
+			}
+		}
+	}
+	return app
+}
+
+// appTracker remembers the app each device was last seen running.
+type appTracker struct {
+	mu   sync.Mutex
+	apps map[string]trackedApp
+}
+
+type trackedApp struct {
+	id     string
+	seenAt time.Time
+}
+
+func newAppTracker() *appTracker {
+	return &appTracker{apps: make(map[string]trackedApp)}
+}
+
+func (t *appTracker) observe(deviceID, app string) {
+	if deviceID == "" || app == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.apps[deviceID] = trackedApp{id: app, seenAt: time.Now()}
+}
+
+func (t *appTracker) last(deviceID string) (string, time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	app := t.apps[deviceID]
+	return app.id, app.seenAt
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+	"github.com/fast-mobile-mcp/shared/snapshot"
+	"github.com/fast-mobile-mcp/worker-ios/internal/device"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const keyboardClass = "XCUIElementTypeKeyboard"
+
+// GetKeyboardState reads the keyboard from the accessibility tree; iOS does not
+// expose the active input method, so ime_package stays empty.
+func (s *MobileService) GetKeyboardState(ctx context.Context, req *mobilev1.GetKeyboardStateRequest) (*mobilev1.GetKeyboardStateResponse, error) {
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	out, err := runtime.Executor.Submit(ctx, executor.LaneQuery, func(runCtx context.Context) (any, error) {
+		return runtime.WDA.DumpHierarchy(runCtx)
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	bounds, shown := keyboardBounds(out.([]snapshot.Node))
+	return &mobilev1.GetKeyboardStateResponse{
+		DeviceId:         req.DeviceId,
+		Shown:            shown,
+		Height:           bounds.Bottom - bounds.Top,
+		Bounds:           convertBounds(bounds),
+		ObservedAtUnixMs: time.Now().UTC().UnixMilli(),
+	}, nil
+}
+
+func (s *MobileService) HideKeyboard(ctx context.Context, req *mobilev1.HideKeyboardRequest) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	out, err := runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		nodes, dumpErr := runtime.WDA.DumpHierarchy(runCtx)
+		if dumpErr != nil {
+			return nil, dumpErr
+		}
+		if _, shown := keyboardBounds(nodes); !shown {
+			return false, nil
+		}
+		return true, runtime.WDA.DismissKeyboard(runCtx)
+	})
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "HIDE_KEYBOARD_FAILED", err), nil
+	}
+	resp := actionOK(req.DeviceId, start)
+	resp.Metadata["was_shown"] = strconv.FormatBool(out.(bool))
+	return resp, nil
+}
+
+// uncoverTarget hides the keyboard when it covers p and returns where the target
+// sits once the layout has settled; a resized view usually moves it.
+func uncoverTarget(ctx context.Context, runtime *device.Runtime, p point, node *snapshot.Node) (point, bool, error) {
+	nodes, err := runtime.WDA.DumpHierarchy(ctx)
+	if err != nil {
+		return p, false, err
+	}
+	bounds, shown := keyboardBounds(nodes)
+	if !shown || !contains(bounds, p) {
+		return p, false, nil
+	}
+	if err := runtime.WDA.DismissKeyboard(ctx); err != nil {
+		return p, false, err
+	}
+	if node == nil {
+		return p, true, nil
+	}
+	nodes, err = runtime.WDA.DumpHierarchy(ctx)
+	if err != nil {
+		return p, true, err
+	}
+	if moved, ok := relocate(*node, nodes); ok {
+		return center(moved.Bounds), true, nil
+	}
+	return p, true, nil
+}
+
+func keyboardBounds(nodes []snapshot.Node) (snapshot.Bounds, bool) {
+	for _, n := range nodes {
+		if n.ClassName == keyboardClass && n.Visible {
+			return n.Bounds, true
+		}
+	}
+	return snapshot.Bounds{}, false
+}
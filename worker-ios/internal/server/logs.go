@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/worker-ios/internal/ios"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const logHeartbeatInterval = 5 * time.Second
+
+// Unified logging message types. Default is os_log's notice level, which is
+// closest to INFO.
+var unifiedLogLevels = map[string]mobilev1.LogLevel{
+	"Debug":   mobilev1.LogLevel_LOG_LEVEL_DEBUG,
+	"Info":    mobilev1.LogLevel_LOG_LEVEL_INFO,
+	"Default": mobilev1.LogLevel_LOG_LEVEL_INFO,
+	"Error":   mobilev1.LogLevel_LOG_LEVEL_ERROR,
+	"Fault":   mobilev1.LogLevel_LOG_LEVEL_FATAL,
+}
+
+// StreamLogs tails the simulator's unified log. It does not go through the
+// executor: the log process lives for the whole stream and must not hold up UI
+// actions. tags match subsystem or category.
+func (s *MobileService) StreamLogs(req *mobilev1.StreamLogsRequest, stream mobilev1.MobileAutomationService_StreamLogsServer) error {
+	if req.SinceUnixMs > 0 {
+		return status.Error(codes.InvalidArgument, "since_unix_ms is not supported by simulator log stream")
+	}
+	if err := checkPredicate(req.Predicate); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	runtime, err := s.registry.RuntimeForDevice(stream.Context(), req.DeviceId)
+	if err != nil {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	var clauses []string
+	if req.Predicate != "" {
+		clauses = append(clauses, "("+req.Predicate+")")
+	}
+	if len(req.Tags) > 0 {
+		quoted := make([]string, 0, len(req.Tags))
+		for _, tag := range req.Tags {
+			quoted = append(quoted, fmt.Sprintf("%q", tag))
+		}
+		set := "{" + strings.Join(quoted, ", ") + "}"
+		clauses = append(clauses, fmt.Sprintf("(subsystem IN %s OR category IN %s)", set, set))
+	}
+	if req.PackageName != "" {
+		container, err := runtime.Simctl.AppContainer(ctx, req.PackageName)
+		if err != nil {
+			return status.Error(codes.FailedPrecondition, fmt.Sprintf("%s is not installed: %v", req.PackageName, err))
+		}
+		clauses = append(clauses, fmt.Sprintf("processImagePath BEGINSWITH %q", container))
+	}
+
+	entries := make(chan ios.LogEntry, 64)
+	done := make(chan error, 1)
+	go func() { done <- runtime.Simctl.LogStream(ctx, strings.Join(clauses, " AND "), entries) }()
+
+	heartbeat := time.NewTicker(logHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	sent := uint32(0)
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-heartbeat.C:
+			if err := stream.Send(&mobilev1.StreamLogsEvent{
+				Payload: &mobilev1.StreamLogsEvent_Heartbeat{Heartbeat: &mobilev1.StreamHeartbeat{UnixMs: time.Now().UTC().UnixMilli()}},
+			}); err != nil {
+				return err
+			}
+		case entry, ok := <-entries:
+			if !ok {
+				if err := <-done; err != nil && stream.Context().Err() == nil {
+					return status.Error(codes.Unavailable, err.Error())
+				}
+				return stream.Send(&mobilev1.StreamLogsEvent{
+					Payload: &mobilev1.StreamLogsEvent_End{End: &mobilev1.StreamEnd{Reason: "log_closed"}},
+				})
+			}
+			converted := convertLogEntry(entry)
+			if req.MinLevel != mobilev1.LogLevel_LOG_LEVEL_UNSPECIFIED && converted.Level < req.MinLevel {
+				continue
+			}
+			if err := stream.Send(&mobilev1.StreamLogsEvent{
+				Payload: &mobilev1.StreamLogsEvent_Entry{Entry: converted},
+			}); err != nil {
+				return err
+			}
+			sent++
+			if req.MaxEntries > 0 && sent >= req.MaxEntries {
+				return stream.Send(&mobilev1.StreamLogsEvent{
+					Payload: &mobilev1.StreamLogsEvent_End{End: &mobilev1.StreamEnd{Reason: "max_entries_reached"}},
+				})
+			}
+		}
+	}
+}
+
+// checkPredicate rejects predicates whose parentheses or quotes do not balance.
+// The predicate is wrapped in parentheses and ANDed with the tag and package
+// scoping, so a predicate such as `x) OR (TRUEPREDICATE` would otherwise escape
+// its group and match every process.
+func checkPredicate(predicate string) error {
+	depth := 0
+	var quote rune
+	escaped := false
+	for _, r := range predicate {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == '\\' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("predicate closes a parenthesis it did not open")
+			}
+		}
+	}
+	if quote != 0 {
+		return fmt.Errorf("predicate has an unterminated string")
+	}
+	if depth != 0 {
+		return fmt.Errorf("predicate has an unclosed parenthesis")
+	}
+	return nil
+}
+
+func convertLogEntry(entry ios.LogEntry) *mobilev1.LogEntry {
+	level, ok := unifiedLogLevels[entry.Type]
+	if !ok {
+		level = mobilev1.LogLevel_LOG_LEVEL_INFO
+	}
+	tag := entry.Subsystem
+	if entry.Category != "" {
+		if tag != "" {
+			tag += ":"
+		}
+		tag += entry.Category
+	}
+	return &mobilev1.LogEntry{
+		TimestampUnixMs: entry.Time.UnixMilli(),
+		Level:           level,
+		Tag:             tag,
+		Pid:             entry.PID,
+		Tid:             entry.TID,
+		Process:         entry.Process,
+		Message:         entry.Message,
+	}
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WDA follows the UIAutomation naming, where plain LANDSCAPE means home button
+// on the right and the reverse variants carry the UIA_DEVICE_ORIENTATION prefix.
+var orientationNames = map[mobilev1.Orientation]string{
+	mobilev1.Orientation_ORIENTATION_PORTRAIT:          "PORTRAIT",
+	mobilev1.Orientation_ORIENTATION_LANDSCAPE:         "LANDSCAPE",
+	mobilev1.Orientation_ORIENTATION_PORTRAIT_REVERSE:  "UIA_DEVICE_ORIENTATION_PORTRAIT_UPSIDEDOWN",
+	mobilev1.Orientation_ORIENTATION_LANDSCAPE_REVERSE: "UIA_DEVICE_ORIENTATION_LANDSCAPERIGHT",
+}
+
+func orientationFromBackend(raw string) mobilev1.Orientation {
+	for o, name := range orientationNames {
+		if strings.EqualFold(name, raw) {
+			return o
+		}
+	}
+	return mobilev1.Orientation_ORIENTATION_UNSPECIFIED
+}
+
+func (s *MobileService) GetOrientation(ctx context.Context, req *mobilev1.GetOrientationRequest) (*mobilev1.GetOrientationResponse, error) {
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	out, err := runtime.Executor.Submit(ctx, executor.LaneQuery, func(runCtx context.Context) (any, error) {
+		return runtime.WDA.Orientation(runCtx)
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &mobilev1.GetOrientationResponse{
+		DeviceId:         req.DeviceId,
+		Orientation:      orientationFromBackend(out.(string)),
+		ObservedAtUnixMs: time.Now().UTC().UnixMilli(),
+	}, nil
+}
+
+func (s *MobileService) SetOrientation(ctx context.Context, req *mobilev1.SetOrientationRequest) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	name, ok := orientationNames[req.Orientation]
+	if !ok {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", errors.New("orientation is required")), nil
+	}
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.actionContext(ctx, req.Options)
+	defer cancel()
+
+	_, err = runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		return nil, runtime.WDA.SetOrientation(runCtx, name)
+	})
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "SET_ORIENTATION_FAILED", err), nil
+	}
+	return actionOK(req.DeviceId, start), nil
+}
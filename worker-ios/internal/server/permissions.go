@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/executor"
+)
+
+// privacyServices maps cross-platform permissions to `simctl privacy` services.
+// The simulator has no camera and simctl cannot pre-answer notification
+// prompts, so those permissions are reported as unsupported.
+var privacyServices = map[mobilev1.Permission]string{
+	mobilev1.Permission_PERMISSION_MICROPHONE:      "microphone",
+	mobilev1.Permission_PERMISSION_LOCATION:        "location",
+	mobilev1.Permission_PERMISSION_LOCATION_ALWAYS: "location-always",
+	mobilev1.Permission_PERMISSION_CONTACTS:        "contacts",
+	mobilev1.Permission_PERMISSION_CALENDAR:        "calendar",
+	mobilev1.Permission_PERMISSION_PHOTOS:          "photos",
+}
+
+func (s *MobileService) GrantPermission(ctx context.Context, req *mobilev1.PermissionRequest) (*mobilev1.ActionResponse, error) {
+	return s.permissionAction(ctx, req, "grant", false, "GRANT_FAILED")
+}
+
+func (s *MobileService) RevokePermission(ctx context.Context, req *mobilev1.PermissionRequest) (*mobilev1.ActionResponse, error) {
+	return s.permissionAction(ctx, req, "revoke", false, "REVOKE_FAILED")
+}
+
+// ResetPermissions returns the permission to the not-determined state so the
+// app prompts again. An unspecified permission resets every service.
+func (s *MobileService) ResetPermissions(ctx context.Context, req *mobilev1.PermissionRequest) (*mobilev1.ActionResponse, error) {
+	return s.permissionAction(ctx, req, "reset", true, "RESET_FAILED")
+}
+
+func (s *MobileService) permissionAction(ctx context.Context, req *mobilev1.PermissionRequest, action string, allowAll bool, failCode string) (*mobilev1.ActionResponse, error) {
+	start := time.Now().UTC()
+	if req.AppId == "" {
+		return actionFailed(req.DeviceId, start, "INVALID_ARGUMENT", errMissingAppID), nil
+	}
+	service, err := resolvePrivacyService(req.Permission, allowAll)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "PERMISSION_UNSUPPORTED", err), nil
+	}
+	runtime, err := s.registry.RuntimeForDevice(ctx, req.DeviceId)
+	if err != nil {
+		return actionFailed(req.DeviceId, start, "DEVICE_NOT_FOUND", err), nil
+	}
+	ctx, cancel := s.timeoutContext(ctx, req.Options, s.cfg.AppTimeout)
+	defer cancel()
+
+	_, err = runtime.Executor.Submit(ctx, executor.LaneInteractive, func(runCtx context.Context) (any, error) {
+		return nil, runtime.Simctl.Privacy(runCtx, action, service, req.AppId)
+	})
+	if err != nil {
+		return actionFailed(req.DeviceId, start, failCode, err), nil
+	}
+	resp := actionOK(req.DeviceId, start)
+	resp.Metadata["permissions"] = service
+	return resp, nil
+}
+
+func resolvePrivacyService(p mobilev1.Permission, allowAll bool) (string, error) {
+	if p == mobilev1.Permission_PERMISSION_UNSPECIFIED {
+		if !allowAll {
+			return "", errors.New("permission is required")
+		}
+		return "all", nil
+	}
+	service, ok := privacyServices[p]
+	if !ok {
+		return "", fmt.Errorf("permission %s is not supported on ios simulators", p)
+	}
+	return service, nil
+}
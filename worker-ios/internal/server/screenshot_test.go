@@ -0,0 +1,75 @@
+package server
+
+import (
+	"image"
+	"testing"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/imaging"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEncodeScreenshotRegionInPoints(t *testing.T) {
+	// A 100x200 point screen at 3x.
+	raw, err := imaging.Encode(image.NewRGBA(image.Rect(0, 0, 300, 600)), imaging.PNG, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		region     *mobilev1.Bounds
+		scale      float64
+		wantWidth  int
+		wantHeight int
+		wantRegion *mobilev1.Bounds
+	}{
+		{
+			name:       "full screen",
+			scale:      3,
+			wantWidth:  300,
+			wantHeight: 600,
+			wantRegion: &mobilev1.Bounds{Right: 100, Bottom: 200},
+		},
+		{
+			name:       "crop inside",
+			region:     &mobilev1.Bounds{Left: 10, Top: 20, Right: 40, Bottom: 60},
+			scale:      3,
+			wantWidth:  90,
+			wantHeight: 120,
+			wantRegion: &mobilev1.Bounds{Left: 10, Top: 20, Right: 40, Bottom: 60},
+		},
+		{
+			name:       "crop clipped to screen",
+			region:     &mobilev1.Bounds{Left: 80, Top: 150, Right: 140, Bottom: 260},
+			scale:      3,
+			wantWidth:  60,
+			wantHeight: 150,
+			wantRegion: &mobilev1.Bounds{Left: 80, Top: 150, Right: 100, Bottom: 200},
+		},
+		{
+			name:       "unknown scale",
+			region:     &mobilev1.Bounds{Left: 10, Top: 10, Right: 20, Bottom: 30},
+			wantWidth:  10,
+			wantHeight: 20,
+			wantRegion: &mobilev1.Bounds{Left: 10, Top: 10, Right: 20, Bottom: 30},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, format, err := encodeScreenshot(raw, mobilev1.ImageFormat_IMAGE_FORMAT_PNG, 0, tt.region, tt.scale)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != imaging.PNG {
+				t.Errorf("format = %v, want png", format)
+			}
+			if got.width != tt.wantWidth || got.height != tt.wantHeight {
+				t.Errorf("size = %dx%d, want %dx%d", got.width, got.height, tt.wantWidth, tt.wantHeight)
+			}
+			if !proto.Equal(got.region, tt.wantRegion) {
+				t.Errorf("region = %v, want %v", got.region, tt.wantRegion)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	mobilev1 "github.com/fast-mobile-mcp/proto/gen/go/mobile/v1"
+	"github.com/fast-mobile-mcp/shared/snapshot"
+	"github.com/fast-mobile-mcp/worker-ios/internal/device"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxWatcherRounds bounds how many interruptions are cleared per dump, so two
+// watchers that keep triggering each other cannot stall the executor.
+const maxWatcherRounds = 3
+
+type watcherSet struct {
+	mu       sync.Mutex
+	byDevice map[string][]*mobilev1.Watcher
+}
+
+func newWatcherSet() *watcherSet {
+	return &watcherSet{byDevice: make(map[string][]*mobilev1.Watcher)}
+}
+
+func (w *watcherSet) add(deviceID string, watcher *mobilev1.Watcher) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.byDevice[deviceID] = append(w.byDevice[deviceID], watcher)
+}
+
+func (w *watcherSet) remove(deviceID, watcherID string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	list := w.byDevice[deviceID]
+	for i, existing := range list {
+		if existing.WatcherId == watcherID {
+			w.byDevice[deviceID] = append(list[:i:i], list[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (w *watcherSet) list(deviceID string) []*mobilev1.Watcher {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	out := make([]*mobilev1.Watcher, 0, len(w.byDevice[deviceID]))
+	for _, watcher := range w.byDevice[deviceID] {
+		out = append(out, proto.Clone(watcher).(*mobilev1.Watcher))
+	}
+	return out
+}
+
+// match returns the first armed watcher whose condition holds in nodes and the
+// node it should tap.
+func (w *watcherSet) match(deviceID string, nodes []snapshot.Node) (*mobilev1.Watcher, snapshot.Node, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, watcher := range w.byDevice[deviceID] {
+		if spent(watcher) {
+			continue
+		}
+		matches := filterNodes(nodes, watcher.When)
+		if len(matches) == 0 {
+			continue
+		}
+		target := matches[0]
+		if watcher.Tap != nil && len(watcher.Tap.Clauses) > 0 {
+			taps := filterNodes(nodes, watcher.Tap)
+			if len(taps) == 0 {
+				continue
+			}
+			target = taps[0]
+		}
+		watcher.TriggerCount++
+		return proto.Clone(watcher).(*mobilev1.Watcher), target, true
+	}
+	return nil, snapshot.Node{}, false
+}
+
+// armed reports whether any of the device's watchers can still trigger.
+func (w *watcherSet) armed(deviceID string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, watcher := range w.byDevice[deviceID] {
+		if !spent(watcher) {
+			return true
+		}
+	}
+	return false
+}
+
+func spent(watcher *mobilev1.Watcher) bool {
+	return watcher.MaxTriggers > 0 && watcher.TriggerCount >= watcher.MaxTriggers
+}
+
+func (s *MobileService) RegisterWatcher(ctx context.Context, req *mobilev1.RegisterWatcherRequest) (*mobilev1.RegisterWatcherResponse, error) {
+	if req.Watcher == nil || req.Watcher.When == nil || len(req.Watcher.When.Clauses) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "watcher.when must have at least one clause")
+	}
+	if _, err := s.registry.RuntimeForDevice(ctx, req.DeviceId); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	watcher := proto.Clone(req.Watcher).(*mobilev1.Watcher)
+	watcher.WatcherId = uuid.NewString()
+	watcher.TriggerCount = 0
+	s.watchers.add(req.DeviceId, watcher)
+
+	return &mobilev1.RegisterWatcherResponse{
+		DeviceId:  req.DeviceId,
+		WatcherId: watcher.WatcherId,
+	}, nil
+}
+
+func (s *MobileService) UnregisterWatcher(ctx context.Context, req *mobilev1.UnregisterWatcherRequest) (*mobilev1.UnregisterWatcherResponse, error) {
+	return &mobilev1.UnregisterWatcherResponse{
+		DeviceId: req.DeviceId,
+		Removed:  s.watchers.remove(req.DeviceId, req.WatcherId),
+	}, nil
+}
+
+func (s *MobileService) ListWatchers(ctx context.Context, req *mobilev1.ListWatchersRequest) (*mobilev1.ListWatchersResponse, error) {
+	return &mobilev1.ListWatchersResponse{
+		DeviceId: req.DeviceId,
+		Watchers: s.watchers.list(req.DeviceId),
+	}, nil
+}
+
+// dumpHierarchy dumps the UI and lets registered watchers clear interruptions
+// before the nodes are handed out. It must run inside an executor job.
+func (s *MobileService) dumpHierarchy(ctx context.Context, runtime *device.Runtime) ([]snapshot.Node, error) {
+	nodes, err := runtime.WDA.DumpHierarchy(ctx)
+	for round := 0; err == nil && round < maxWatcherRounds; round++ {
+		watcher, target, ok := s.watchers.match(runtime.DeviceID, nodes)
+		if !ok {
+			break
+		}
+		p := center(target.Bounds)
+		s.log.Info("watcher triggered", "device_id", runtime.DeviceID, "watcher_id", watcher.WatcherId, "name", watcher.Name, "ref_id", target.RefID)
+		if err = runtime.WDA.Tap(ctx, p.x, p.y, 1); err != nil {
+			return nil, err
+		}
+		nodes, err = runtime.WDA.DumpHierarchy(ctx)
+	}
+	return nodes, err
+}
+
+// runWatchers checks for interruptions ahead of an action. It only costs a dump
+// when the device has watchers that can still trigger.
+func (s *MobileService) runWatchers(ctx context.Context, runtime *device.Runtime) error {
+	if !s.watchers.armed(runtime.DeviceID) {
+		return nil
+	}
+	_, err := s.dumpHierarchy(ctx, runtime)
+	return err
+}